@@ -2,15 +2,20 @@ package ast
 
 import (
 	"bytes"
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/token"
 )
 
 type Node interface {
 	TokenLiteral() string
+	Pos() token.Position
 	String() string
 }
 
 type Program struct {
 	Statements []Statement
+	Comments   []*Comment
 }
 
 func (prog *Program) TokenLiteral() string {
@@ -21,6 +26,14 @@ func (prog *Program) TokenLiteral() string {
 	}
 }
 
+func (prog *Program) Pos() token.Position {
+	if len(prog.Statements) > 0 {
+		return prog.Statements[0].Pos()
+	}
+
+	return token.Position{}
+}
+
 func (p *Program) String() string {
 	var out bytes.Buffer
 
@@ -30,3 +43,17 @@ func (p *Program) String() string {
 
 	return out.String()
 }
+
+// Comment is a line comment. Comments are not part of the tree, the program
+// keeps them in source order. Trailing comments follow code on their line.
+type Comment struct {
+	Token    token.Token
+	Trailing bool
+}
+
+func (comment *Comment) Pos() token.Position { return comment.Token.Position }
+
+// Text returns the comment without the leading slashes and spaces.
+func (comment *Comment) Text() string {
+	return strings.TrimSpace(strings.TrimPrefix(comment.Token.Literal, "//"))
+}
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/parser"
 	"github.com/henningstorck/monkey-interpreter/token"
 	"github.com/stretchr/testify/assert"
 )
@@ -25,3 +27,62 @@ func TestString(t *testing.T) {
 
 	assert.Equal(t, "let myVar = anotherVar;", program.String())
 }
+
+func TestWalk(t *testing.T) {
+	lex := lexer.NewLexer(`let {a, b: [c]} = f(x.y, key: z); fn g(d = e) { if (d) { h } }`)
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+	assert.Empty(t, par.Errors())
+	names := []string{}
+
+	ast.Walk(program, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Identifier); ok {
+			names = append(names, ident.Value)
+		}
+
+		return true
+	})
+
+	assert.Equal(t, []string{"a", "c", "f", "x", "z", "g", "d", "e", "d", "h"}, names)
+}
+
+func TestWalkDeclarations(t *testing.T) {
+	lex := lexer.NewLexer(`let [a, _, ...b] = x; if (a) { let c = 1 } fn d() { let e = 2 }
+match (if (a) { let f = 3; f }) { g => if (g) { let h = 4 } }
+try { let i = 5 } catch (j) { let k = 6 } finally { let l = 7 }`)
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+	assert.Empty(t, par.Errors())
+	names := []string{}
+
+	ast.WalkDeclarations(program, func(ident *ast.Identifier, _ ast.Statement) {
+		names = append(names, ident.Value)
+	})
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "f", "i", "l"}, names)
+}
+
+func TestArity(t *testing.T) {
+	tests := []struct {
+		input    string
+		min, max int
+	}{
+		{"fn() {}", 0, 0},
+		{"fn(a, b) {}", 2, 2},
+		{"fn(a, b = 1) {}", 1, 2},
+		{"fn(a = 1, b = 2) {}", 0, 2},
+		{"fn(a, ...b) {}", 1, -1},
+	}
+
+	for _, test := range tests {
+		lex := lexer.NewLexer(test.input)
+		par := parser.NewParser(lex)
+		program := par.ParseProgram()
+		assert.Empty(t, par.Errors(), test.input)
+
+		fnLiteral := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+		min, max := fnLiteral.Arity()
+		assert.Equal(t, test.min, min, test.input)
+		assert.Equal(t, test.max, max, test.input)
+	}
+}
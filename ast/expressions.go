@@ -20,6 +20,7 @@ type PrefixExpression struct {
 
 func (prefixExp *PrefixExpression) expressionNode()      {}
 func (prefixExp *PrefixExpression) TokenLiteral() string { return prefixExp.Token.Literal }
+func (prefixExp *PrefixExpression) Pos() token.Position  { return prefixExp.Token.Position }
 
 func (pe *PrefixExpression) String() string {
 	var out bytes.Buffer
@@ -39,6 +40,7 @@ type InfixExpression struct {
 
 func (infixExp *InfixExpression) expressionNode()      {}
 func (infixExp *InfixExpression) TokenLiteral() string { return infixExp.Token.Literal }
+func (infixExp *InfixExpression) Pos() token.Position  { return infixExp.Token.Position }
 
 func (infixExp *InfixExpression) String() string {
 	var out bytes.Buffer
@@ -59,6 +61,7 @@ type IfExpression struct {
 
 func (ifExp IfExpression) expressionNode()      {}
 func (ifExp IfExpression) TokenLiteral() string { return ifExp.Token.Literal }
+func (ifExp IfExpression) Pos() token.Position  { return ifExp.Token.Position }
 
 func (ifExp IfExpression) String() string {
 	var out bytes.Buffer
@@ -76,14 +79,50 @@ func (ifExp IfExpression) String() string {
 	return out.String()
 }
 
+type TryExpression struct {
+	Token       token.Token
+	Block       *BlockStatement
+	Parameter   *Identifier
+	Catch       *BlockStatement
+	CatchLocals []string
+	Finally     *BlockStatement
+}
+
+func (tryExp *TryExpression) expressionNode()      {}
+func (tryExp *TryExpression) TokenLiteral() string { return tryExp.Token.Literal }
+func (tryExp *TryExpression) Pos() token.Position  { return tryExp.Token.Position }
+
+func (tryExp *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(tryExp.Block.String())
+
+	if tryExp.Catch != nil {
+		out.WriteString(" catch(")
+		out.WriteString(tryExp.Parameter.String())
+		out.WriteString(") ")
+		out.WriteString(tryExp.Catch.String())
+	}
+
+	if tryExp.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(tryExp.Finally.String())
+	}
+
+	return out.String()
+}
+
 type CallExpression struct {
 	Token     token.Token
 	Function  Expression // identifier or function literal
 	Arguments []Expression
+	Keywords  []*KeywordArgument
 }
 
 func (callExp *CallExpression) expressionNode()      {}
 func (callExp *CallExpression) TokenLiteral() string { return callExp.Token.Literal }
+func (callExp *CallExpression) Pos() token.Position  { return callExp.Token.Position }
 
 func (callExp *CallExpression) String() string {
 	var out bytes.Buffer
@@ -93,6 +132,10 @@ func (callExp *CallExpression) String() string {
 		args = append(args, arg.String())
 	}
 
+	for _, keyword := range callExp.Keywords {
+		args = append(args, keyword.Name.String()+": "+keyword.Value.String())
+	}
+
 	out.WriteString(callExp.Function.String())
 	out.WriteString("(")
 	out.WriteString(strings.Join(args, ", "))
@@ -100,21 +143,224 @@ func (callExp *CallExpression) String() string {
 	return out.String()
 }
 
-type IndexExpression struct {
+// KeywordArgument passes Value to the parameter called Name.
+type KeywordArgument struct {
+	Name  *Identifier
+	Value Expression
+}
+
+// SpreadExpression passes the elements of an array as separate arguments or
+// array elements.
+type SpreadExpression struct {
 	Token token.Token
-	Left  Expression
-	Index Expression
+	Value Expression
+}
+
+func (spreadExp *SpreadExpression) expressionNode()      {}
+func (spreadExp *SpreadExpression) TokenLiteral() string { return spreadExp.Token.Literal }
+func (spreadExp *SpreadExpression) Pos() token.Position  { return spreadExp.Token.Position }
+func (spreadExp *SpreadExpression) String() string       { return "..." + spreadExp.Value.String() }
+
+// IndexExpression, SliceExpression and MemberExpression evaluate to null
+// instead of failing if they are Optional and their left side is null.
+type IndexExpression struct {
+	Token    token.Token
+	Left     Expression
+	Index    Expression
+	Optional bool
 }
 
 func (indexExp IndexExpression) expressionNode()      {}
 func (indexExp IndexExpression) TokenLiteral() string { return indexExp.Token.Literal }
+func (indexExp IndexExpression) Pos() token.Position  { return indexExp.Token.Position }
 
 func (indexExp IndexExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("(")
 	out.WriteString(indexExp.Left.String())
+	out.WriteString(optionalChain(indexExp.Optional))
 	out.WriteString("[")
 	out.WriteString(indexExp.Index.String())
 	out.WriteString("])")
 	return out.String()
 }
+
+type SliceExpression struct {
+	Token    token.Token
+	Left     Expression
+	Start    Expression
+	End      Expression
+	Step     Expression
+	Optional bool
+}
+
+func (sliceExp *SliceExpression) expressionNode()      {}
+func (sliceExp *SliceExpression) TokenLiteral() string { return sliceExp.Token.Literal }
+func (sliceExp *SliceExpression) Pos() token.Position  { return sliceExp.Token.Position }
+
+func (sliceExp *SliceExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(sliceExp.Left.String())
+	out.WriteString(optionalChain(sliceExp.Optional))
+	out.WriteString("[")
+
+	if sliceExp.Start != nil {
+		out.WriteString(sliceExp.Start.String())
+	}
+
+	out.WriteString(":")
+
+	if sliceExp.End != nil {
+		out.WriteString(sliceExp.End.String())
+	}
+
+	if sliceExp.Step != nil {
+		out.WriteString(":")
+		out.WriteString(sliceExp.Step.String())
+	}
+
+	out.WriteString("])")
+	return out.String()
+}
+
+type MemberExpression struct {
+	Token    token.Token
+	Left     Expression
+	Property *Identifier
+	Optional bool
+}
+
+func (memberExp *MemberExpression) expressionNode()      {}
+func (memberExp *MemberExpression) TokenLiteral() string { return memberExp.Token.Literal }
+func (memberExp *MemberExpression) Pos() token.Position  { return memberExp.Token.Position }
+
+func (memberExp *MemberExpression) String() string {
+	if memberExp.Optional {
+		return "(" + memberExp.Left.String() + "?." + memberExp.Property.String() + ")"
+	}
+
+	return "(" + memberExp.Left.String() + "." + memberExp.Property.String() + ")"
+}
+
+func optionalChain(optional bool) string {
+	if optional {
+		return "?."
+	}
+
+	return ""
+}
+
+type ConditionalExpression struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (condExp *ConditionalExpression) expressionNode()      {}
+func (condExp *ConditionalExpression) TokenLiteral() string { return condExp.Token.Literal }
+func (condExp *ConditionalExpression) Pos() token.Position  { return condExp.Token.Position }
+
+func (condExp *ConditionalExpression) String() string {
+	return "(" + condExp.Condition.String() + " ? " + condExp.Consequence.String() + " : " + condExp.Alternative.String() + ")"
+}
+
+type ImportExpression struct {
+	Token token.Token
+	Path  Expression
+}
+
+func (importExp *ImportExpression) expressionNode()      {}
+func (importExp *ImportExpression) TokenLiteral() string { return importExp.Token.Literal }
+func (importExp *ImportExpression) Pos() token.Position  { return importExp.Token.Position }
+
+func (importExp *ImportExpression) String() string {
+	return importExp.TokenLiteral() + " " + importExp.Path.String()
+}
+
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expression
+	Body    Expression
+	Locals  []string
+}
+
+func (arm *MatchArm) String() string {
+	var out bytes.Buffer
+	out.WriteString(arm.Pattern.String())
+
+	if arm.Guard != nil {
+		out.WriteString(" if " + arm.Guard.String())
+	}
+
+	out.WriteString(" => " + arm.Body.String())
+	return out.String()
+}
+
+type MatchExpression struct {
+	Token   token.Token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (matchExp *MatchExpression) expressionNode()      {}
+func (matchExp *MatchExpression) TokenLiteral() string { return matchExp.Token.Literal }
+func (matchExp *MatchExpression) Pos() token.Position  { return matchExp.Token.Position }
+
+func (matchExp *MatchExpression) String() string {
+	var out bytes.Buffer
+	arms := []string{}
+
+	for _, arm := range matchExp.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match " + matchExp.Subject.String() + " { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+	return out.String()
+}
+
+type SwitchCase struct {
+	Token  token.Token
+	Values []Expression
+	Body   *BlockStatement
+}
+
+func (switchCase *SwitchCase) String() string {
+	values := []string{}
+
+	for _, value := range switchCase.Values {
+		values = append(values, value.String())
+	}
+
+	return "case " + strings.Join(values, ", ") + ": " + switchCase.Body.String()
+}
+
+type SwitchExpression struct {
+	Token   token.Token
+	Subject Expression
+	Cases   []*SwitchCase
+	Default *BlockStatement
+}
+
+func (switchExp *SwitchExpression) expressionNode()      {}
+func (switchExp *SwitchExpression) TokenLiteral() string { return switchExp.Token.Literal }
+func (switchExp *SwitchExpression) Pos() token.Position  { return switchExp.Token.Position }
+
+func (switchExp *SwitchExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("switch " + switchExp.Subject.String() + " {")
+
+	for _, switchCase := range switchExp.Cases {
+		out.WriteString(" " + switchCase.String())
+	}
+
+	if switchExp.Default != nil {
+		out.WriteString(" default: " + switchExp.Default.String())
+	}
+
+	out.WriteString(" }")
+	return out.String()
+}
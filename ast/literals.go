@@ -7,22 +7,32 @@ import (
 	"github.com/henningstorck/monkey-interpreter/token"
 )
 
+// Identifier is bound to a slot of the frame Depth scopes up once Resolved.
+// Unresolved identifiers, like globals, are looked up by name.
 type Identifier struct {
-	Token token.Token
-	Value string
+	Token    token.Token
+	Value    string
+	Resolved bool
+	Depth    int
+	Slot     int
 }
 
 func (ident *Identifier) expressionNode()      {}
 func (ident *Identifier) TokenLiteral() string { return ident.Token.Literal }
+func (ident *Identifier) Pos() token.Position  { return ident.Token.Position }
 func (ident *Identifier) String() string       { return ident.Value }
 
+// IntegerLiteral and StringLiteral keep the object they evaluate to in Object,
+// so it is allocated only once.
 type IntegerLiteral struct {
-	Token token.Token
-	Value int64
+	Token  token.Token
+	Value  int64
+	Object any
 }
 
 func (intLiteral *IntegerLiteral) expressionNode()      {}
 func (intLiteral *IntegerLiteral) TokenLiteral() string { return intLiteral.Token.Literal }
+func (intLiteral *IntegerLiteral) Pos() token.Position  { return intLiteral.Token.Position }
 func (intLiteral *IntegerLiteral) String() string       { return intLiteral.Token.Literal }
 
 type BooleanLiteral struct {
@@ -32,40 +42,60 @@ type BooleanLiteral struct {
 
 func (boolLiteral *BooleanLiteral) expressionNode()      {}
 func (boolLiteral *BooleanLiteral) TokenLiteral() string { return boolLiteral.Token.Literal }
+func (boolLiteral *BooleanLiteral) Pos() token.Position  { return boolLiteral.Token.Position }
 func (boolLiteral *BooleanLiteral) String() string       { return boolLiteral.Token.Literal }
 
+// FunctionLiteral has a default value in Defaults and a type annotation in
+// Types for each of its Parameters, which are nil or empty if not given. The
+// remaining arguments are collected in Rest. Only function declarations have a
+// Name. Locals names the slots of its frames once resolved.
 type FunctionLiteral struct {
 	Token      token.Token
+	Name       *Identifier
 	Parameters []*Identifier
+	Types      []string
+	Defaults   []Expression
+	Rest       *Identifier
+	ReturnType string
 	Body       *BlockStatement
+	Locals     []string
 }
 
 func (fnLiteral FunctionLiteral) expressionNode()      {}
 func (fnLiteral FunctionLiteral) TokenLiteral() string { return fnLiteral.Token.Literal }
+func (fnLiteral FunctionLiteral) Pos() token.Position  { return fnLiteral.Token.Position }
 
 func (fnLiteral FunctionLiteral) String() string {
 	var out bytes.Buffer
-	params := []string{}
+	out.WriteString(fnLiteral.TokenLiteral())
 
-	for _, param := range fnLiteral.Parameters {
-		params = append(params, param.String())
+	if fnLiteral.Name != nil {
+		out.WriteString(" " + fnLiteral.Name.String())
 	}
 
-	out.WriteString(fnLiteral.TokenLiteral())
 	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(") ")
+	out.WriteString(ParametersString(fnLiteral.Parameters, fnLiteral.Types, fnLiteral.Defaults, fnLiteral.Rest))
+	out.WriteString(")")
+
+	if fnLiteral.ReturnType != "" {
+		out.WriteString(": " + fnLiteral.ReturnType)
+	}
+
+	out.WriteString(" ")
+
 	out.WriteString(fnLiteral.Body.String())
 	return out.String()
 }
 
 type StringLiteral struct {
-	Token token.Token
-	Value string
+	Token  token.Token
+	Value  string
+	Object any
 }
 
 func (stringLiteral *StringLiteral) expressionNode()      {}
 func (stringLiteral *StringLiteral) TokenLiteral() string { return stringLiteral.Token.Literal }
+func (stringLiteral *StringLiteral) Pos() token.Position  { return stringLiteral.Token.Position }
 func (stringLiteral *StringLiteral) String() string       { return stringLiteral.Token.Literal }
 
 type ArrayLiteral struct {
@@ -75,6 +105,7 @@ type ArrayLiteral struct {
 
 func (arrLiteral ArrayLiteral) expressionNode()      {}
 func (arrLiteral ArrayLiteral) TokenLiteral() string { return arrLiteral.Token.Literal }
+func (arrLiteral ArrayLiteral) Pos() token.Position  { return arrLiteral.Token.Position }
 
 func (arrLiteral ArrayLiteral) String() string {
 	var out bytes.Buffer
@@ -89,3 +120,75 @@ func (arrLiteral ArrayLiteral) String() string {
 	out.WriteString("]")
 	return out.String()
 }
+
+type HashLiteral struct {
+	Token  token.Token
+	Keys   []Expression
+	Values []Expression
+}
+
+func (hashLiteral *HashLiteral) expressionNode()      {}
+func (hashLiteral *HashLiteral) TokenLiteral() string { return hashLiteral.Token.Literal }
+func (hashLiteral *HashLiteral) Pos() token.Position  { return hashLiteral.Token.Position }
+
+func (hashLiteral *HashLiteral) String() string {
+	var out bytes.Buffer
+	pairs := []string{}
+
+	for i, key := range hashLiteral.Keys {
+		pairs = append(pairs, key.String()+": "+hashLiteral.Values[i].String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// Arity returns the number of required parameters and the maximum number of
+// arguments, which is -1 for functions with rest parameters.
+func (fnLiteral FunctionLiteral) Arity() (int, int) {
+	return ParametersArity(fnLiteral.Parameters, fnLiteral.Defaults, fnLiteral.Rest)
+}
+
+// ParametersArity is the arity of functions with the given parameters. A
+// parameter is required unless it and all parameters after it have defaults.
+func ParametersArity(params []*Identifier, defaults []Expression, rest *Identifier) (int, int) {
+	min := 0
+
+	for i := range params {
+		if i >= len(defaults) || defaults[i] == nil {
+			min = i + 1
+		}
+	}
+
+	if rest != nil {
+		return min, -1
+	}
+
+	return min, len(params)
+}
+
+func ParametersString(params []*Identifier, types []string, defaults []Expression, rest *Identifier) string {
+	list := []string{}
+
+	for i, param := range params {
+		str := param.String()
+
+		if i < len(types) && types[i] != "" {
+			str += ": " + types[i]
+		}
+
+		if i < len(defaults) && defaults[i] != nil {
+			str += " = " + defaults[i].String()
+		}
+
+		list = append(list, str)
+	}
+
+	if rest != nil {
+		list = append(list, "..."+rest.String())
+	}
+
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,189 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+// Pattern is the target of a destructuring binding.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+// Types lists the names of all value types, as used by type patterns.
+var Types = []string{"int", "string", "bool", "array", "hash", "fn", "null", "module", "exception"}
+
+func IsType(name string) bool {
+	for _, typeName := range Types {
+		if typeName == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ident *Identifier) patternNode()            {}
+func (intLiteral *IntegerLiteral) patternNode()   {}
+func (boolLiteral *BooleanLiteral) patternNode()  {}
+func (stringLiteral *StringLiteral) patternNode() {}
+
+// IsWildcard reports whether the pattern matches anything without binding it.
+func IsWildcard(pattern Pattern) bool {
+	ident, ok := pattern.(*Identifier)
+	return ok && ident.Value == "_"
+}
+
+type ArrayPattern struct {
+	Token    token.Token
+	Elements []Pattern
+	Rest     *Identifier
+}
+
+func (arrPattern *ArrayPattern) patternNode()         {}
+func (arrPattern *ArrayPattern) TokenLiteral() string { return arrPattern.Token.Literal }
+func (arrPattern *ArrayPattern) Pos() token.Position  { return arrPattern.Token.Position }
+
+func (arrPattern *ArrayPattern) String() string {
+	var out bytes.Buffer
+	elements := []string{}
+
+	for _, element := range arrPattern.Elements {
+		elements = append(elements, element.String())
+	}
+
+	if arrPattern.Rest != nil {
+		elements = append(elements, "..."+arrPattern.Rest.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+	return out.String()
+}
+
+type HashPatternEntry struct {
+	Key   *StringLiteral
+	Value Pattern
+}
+
+type HashPattern struct {
+	Token   token.Token
+	Entries []*HashPatternEntry
+}
+
+func (hashPattern *HashPattern) patternNode()         {}
+func (hashPattern *HashPattern) TokenLiteral() string { return hashPattern.Token.Literal }
+func (hashPattern *HashPattern) Pos() token.Position  { return hashPattern.Token.Position }
+
+func (hashPattern *HashPattern) String() string {
+	var out bytes.Buffer
+	entries := []string{}
+
+	for _, entry := range hashPattern.Entries {
+		if isShorthand(entry) {
+			entries = append(entries, entry.Value.String())
+		} else {
+			entries = append(entries, entry.Key.String()+": "+entry.Value.String())
+		}
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(entries, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// Entries like {name} bind the value to a variable named after its key
+func isShorthand(entry *HashPatternEntry) bool {
+	target := entry.Value
+
+	if defaultPattern, ok := target.(*DefaultPattern); ok {
+		target = defaultPattern.Target
+	}
+
+	ident, ok := target.(*Identifier)
+	return ok && ident.Value == entry.Key.Value
+}
+
+// DefaultPattern binds Default whenever the destructured value is missing.
+type DefaultPattern struct {
+	Token   token.Token
+	Target  Pattern
+	Default Expression
+}
+
+func (defaultPattern *DefaultPattern) patternNode()         {}
+func (defaultPattern *DefaultPattern) TokenLiteral() string { return defaultPattern.Token.Literal }
+func (defaultPattern *DefaultPattern) Pos() token.Position  { return defaultPattern.Target.Pos() }
+
+func (defaultPattern *DefaultPattern) String() string {
+	return defaultPattern.Target.String() + " = " + defaultPattern.Default.String()
+}
+
+// TypePattern matches values of the given type and binds them to Target.
+type TypePattern struct {
+	Token  token.Token
+	Target *Identifier
+	Type   string
+}
+
+func (typePattern *TypePattern) patternNode()         {}
+func (typePattern *TypePattern) TokenLiteral() string { return typePattern.Token.Literal }
+func (typePattern *TypePattern) Pos() token.Position  { return typePattern.Target.Pos() }
+
+func (typePattern *TypePattern) String() string {
+	return typePattern.Target.String() + ": " + typePattern.Type
+}
+
+// PatternNames lists the names bound by a pattern in source order.
+func PatternNames(pattern Pattern) []string {
+	names := []string{}
+
+	for _, ident := range PatternIdentifiers(pattern) {
+		names = append(names, ident.Value)
+	}
+
+	return names
+}
+
+// PatternIdentifiers lists the identifiers bound by a pattern in source order.
+func PatternIdentifiers(pattern Pattern) []*Identifier {
+	switch pattern := pattern.(type) {
+	case *Identifier:
+		if IsWildcard(pattern) {
+			return []*Identifier{}
+		}
+
+		return []*Identifier{pattern}
+	case *TypePattern:
+		return PatternIdentifiers(pattern.Target)
+	case *DefaultPattern:
+		return PatternIdentifiers(pattern.Target)
+	case *ArrayPattern:
+		idents := []*Identifier{}
+
+		for _, element := range pattern.Elements {
+			idents = append(idents, PatternIdentifiers(element)...)
+		}
+
+		if pattern.Rest != nil {
+			idents = append(idents, pattern.Rest)
+		}
+
+		return idents
+	case *HashPattern:
+		idents := []*Identifier{}
+
+		for _, entry := range pattern.Entries {
+			idents = append(idents, PatternIdentifiers(entry.Value)...)
+		}
+
+		return idents
+	default:
+		return []*Identifier{}
+	}
+}
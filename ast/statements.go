@@ -11,19 +11,34 @@ type Statement interface {
 	statementNode()
 }
 
+// LetStatement binds either a single Name or destructures its value into a
+// Pattern. A Name may be annotated with a Type.
 type LetStatement struct {
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Token   token.Token
+	Name    *Identifier
+	Type    string
+	Pattern Pattern
+	Value   Expression
 }
 
 func (letStmt *LetStatement) statementNode()       {}
 func (letStmt *LetStatement) TokenLiteral() string { return letStmt.Token.Literal }
+func (letStmt *LetStatement) Pos() token.Position  { return letStmt.Token.Position }
 
 func (letStmt *LetStatement) String() string {
 	var out bytes.Buffer
 	out.WriteString(letStmt.TokenLiteral() + " ")
-	out.WriteString(letStmt.Name.String())
+
+	if letStmt.Pattern != nil {
+		out.WriteString(letStmt.Pattern.String())
+	} else {
+		out.WriteString(letStmt.Name.String())
+	}
+
+	if letStmt.Type != "" {
+		out.WriteString(": " + letStmt.Type)
+	}
+
 	out.WriteString(" = ")
 
 	if letStmt.Value != nil {
@@ -34,6 +49,40 @@ func (letStmt *LetStatement) String() string {
 	return out.String()
 }
 
+// Names lists all names bound by the statement.
+func (letStmt *LetStatement) Names() []string {
+	if letStmt.Pattern != nil {
+		return PatternNames(letStmt.Pattern)
+	}
+
+	return []string{letStmt.Name.Value}
+}
+
+type ExportStatement struct {
+	Token     token.Token
+	Statement *LetStatement
+}
+
+func (exportStmt *ExportStatement) statementNode()       {}
+func (exportStmt *ExportStatement) TokenLiteral() string { return exportStmt.Token.Literal }
+func (exportStmt *ExportStatement) Pos() token.Position  { return exportStmt.Token.Position }
+
+func (exportStmt *ExportStatement) String() string {
+	return exportStmt.TokenLiteral() + " " + exportStmt.Statement.String()
+}
+
+// FunctionDeclaration binds a named function before any other statement of
+// its block is evaluated.
+type FunctionDeclaration struct {
+	Token    token.Token
+	Function *FunctionLiteral
+}
+
+func (fnDecl *FunctionDeclaration) statementNode()       {}
+func (fnDecl *FunctionDeclaration) TokenLiteral() string { return fnDecl.Token.Literal }
+func (fnDecl *FunctionDeclaration) Pos() token.Position  { return fnDecl.Token.Position }
+func (fnDecl *FunctionDeclaration) String() string       { return fnDecl.Function.String() }
+
 type ReturnStatement struct {
 	Token       token.Token
 	ReturnValue Expression
@@ -41,6 +90,7 @@ type ReturnStatement struct {
 
 func (returnStmt *ReturnStatement) statementNode()       {}
 func (returnStmt *ReturnStatement) TokenLiteral() string { return returnStmt.Token.Literal }
+func (returnStmt *ReturnStatement) Pos() token.Position  { return returnStmt.Token.Position }
 
 func (returnStmt *ReturnStatement) String() string {
 	var out bytes.Buffer
@@ -54,6 +104,27 @@ func (returnStmt *ReturnStatement) String() string {
 	return out.String()
 }
 
+type ThrowStatement struct {
+	Token token.Token
+	Value Expression
+}
+
+func (throwStmt *ThrowStatement) statementNode()       {}
+func (throwStmt *ThrowStatement) TokenLiteral() string { return throwStmt.Token.Literal }
+func (throwStmt *ThrowStatement) Pos() token.Position  { return throwStmt.Token.Position }
+
+func (throwStmt *ThrowStatement) String() string {
+	var out bytes.Buffer
+	out.WriteString(throwStmt.TokenLiteral() + " ")
+
+	if throwStmt.Value != nil {
+		out.WriteString(throwStmt.Value.String())
+	}
+
+	out.WriteString(";")
+	return out.String()
+}
+
 type ExpressionStatement struct {
 	Token      token.Token
 	Expression Expression
@@ -61,6 +132,7 @@ type ExpressionStatement struct {
 
 func (expressionStmt *ExpressionStatement) statementNode()       {}
 func (expressionStmt *ExpressionStatement) TokenLiteral() string { return expressionStmt.Token.Literal }
+func (expressionStmt *ExpressionStatement) Pos() token.Position  { return expressionStmt.Token.Position }
 
 func (expressionStmt *ExpressionStatement) String() string {
 	if expressionStmt.Expression != nil {
@@ -77,6 +149,7 @@ type BlockStatement struct {
 
 func (blockStmt BlockStatement) statementNode()       {}
 func (blockStmt BlockStatement) TokenLiteral() string { return blockStmt.Token.Literal }
+func (blockStmt BlockStatement) Pos() token.Position  { return blockStmt.Token.Position }
 
 func (blockStmt BlockStatement) String() string {
 	var out bytes.Buffer
@@ -0,0 +1,195 @@
+package ast
+
+// Walk traverses the tree in source order, calling visit for each node before
+// its children. Children are skipped if visit returns false. Property names,
+// keyword names and the keys of hash patterns are not visited, as they do not
+// refer to bindings.
+func Walk(node Node, visit func(Node) bool) {
+	if !visit(node) {
+		return
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		walkStatements(node.Statements, visit)
+	case *BlockStatement:
+		walkStatements(node.Statements, visit)
+	case *LetStatement:
+		if node.Pattern != nil {
+			Walk(node.Pattern, visit)
+		} else {
+			Walk(node.Name, visit)
+		}
+
+		walkExpression(node.Value, visit)
+	case *ExportStatement:
+		Walk(node.Statement, visit)
+	case *FunctionDeclaration:
+		Walk(node.Function, visit)
+	case *ReturnStatement:
+		walkExpression(node.ReturnValue, visit)
+	case *ThrowStatement:
+		walkExpression(node.Value, visit)
+	case *ExpressionStatement:
+		walkExpression(node.Expression, visit)
+	case *FunctionLiteral:
+		walkIdentifier(node.Name, visit)
+
+		for i, param := range node.Parameters {
+			Walk(param, visit)
+
+			if i < len(node.Defaults) {
+				walkExpression(node.Defaults[i], visit)
+			}
+		}
+
+		walkIdentifier(node.Rest, visit)
+		walkBlock(node.Body, visit)
+	case *ArrayLiteral:
+		walkExpressions(node.Elements, visit)
+	case *HashLiteral:
+		for i, key := range node.Keys {
+			walkExpression(key, visit)
+			walkExpression(node.Values[i], visit)
+		}
+	case *PrefixExpression:
+		walkExpression(node.Right, visit)
+	case *InfixExpression:
+		walkExpression(node.Left, visit)
+		walkExpression(node.Right, visit)
+	case *ConditionalExpression:
+		walkExpression(node.Condition, visit)
+		walkExpression(node.Consequence, visit)
+		walkExpression(node.Alternative, visit)
+	case *IfExpression:
+		walkExpression(node.Condition, visit)
+		walkBlock(node.Consequence, visit)
+		walkBlock(node.Alternative, visit)
+	case *TryExpression:
+		walkBlock(node.Block, visit)
+		walkIdentifier(node.Parameter, visit)
+		walkBlock(node.Catch, visit)
+		walkBlock(node.Finally, visit)
+	case *CallExpression:
+		walkExpression(node.Function, visit)
+		walkExpressions(node.Arguments, visit)
+
+		for _, keyword := range node.Keywords {
+			walkExpression(keyword.Value, visit)
+		}
+	case *SpreadExpression:
+		walkExpression(node.Value, visit)
+	case *IndexExpression:
+		walkExpression(node.Left, visit)
+		walkExpression(node.Index, visit)
+	case *SliceExpression:
+		walkExpression(node.Left, visit)
+		walkExpression(node.Start, visit)
+		walkExpression(node.End, visit)
+		walkExpression(node.Step, visit)
+	case *MemberExpression:
+		walkExpression(node.Left, visit)
+	case *ImportExpression:
+		walkExpression(node.Path, visit)
+	case *MatchExpression:
+		walkExpression(node.Subject, visit)
+
+		for _, arm := range node.Arms {
+			Walk(arm.Pattern, visit)
+			walkExpression(arm.Guard, visit)
+			walkExpression(arm.Body, visit)
+		}
+	case *SwitchExpression:
+		walkExpression(node.Subject, visit)
+
+		for _, switchCase := range node.Cases {
+			walkExpressions(switchCase.Values, visit)
+			walkBlock(switchCase.Body, visit)
+		}
+
+		walkBlock(node.Default, visit)
+	case *ArrayPattern:
+		for _, element := range node.Elements {
+			Walk(element, visit)
+		}
+
+		walkIdentifier(node.Rest, visit)
+	case *HashPattern:
+		for _, entry := range node.Entries {
+			Walk(entry.Value, visit)
+		}
+	case *DefaultPattern:
+		Walk(node.Target, visit)
+		walkExpression(node.Default, visit)
+	case *TypePattern:
+		Walk(node.Target, visit)
+	}
+}
+
+func walkStatements(stmts []Statement, visit func(Node) bool) {
+	for _, stmt := range stmts {
+		Walk(stmt, visit)
+	}
+}
+
+func walkExpressions(exps []Expression, visit func(Node) bool) {
+	for _, exp := range exps {
+		walkExpression(exp, visit)
+	}
+}
+
+// Optional children are skipped when they are missing
+func walkExpression(exp Expression, visit func(Node) bool) {
+	if exp != nil {
+		Walk(exp, visit)
+	}
+}
+
+func walkBlock(block *BlockStatement, visit func(Node) bool) {
+	if block != nil {
+		Walk(block, visit)
+	}
+}
+
+func walkIdentifier(ident *Identifier, visit func(Node) bool) {
+	if ident != nil {
+		Walk(ident, visit)
+	}
+}
+
+// WalkDeclarations calls declare for each name bound by a let statement or a
+// function declaration in the scope of the node, in source order. Function
+// literals, match arms and catch blocks are scopes of their own and are not
+// descended into.
+func WalkDeclarations(node Node, declare func(ident *Identifier, stmt Statement)) {
+	Walk(node, func(node Node) bool {
+		switch node := node.(type) {
+		case *LetStatement:
+			if node.Pattern != nil {
+				for _, ident := range PatternIdentifiers(node.Pattern) {
+					declare(ident, node)
+				}
+			} else {
+				declare(node.Name, node)
+			}
+		case *FunctionDeclaration:
+			declare(node.Function.Name, node)
+			return false
+		case *FunctionLiteral:
+			return false
+		case *MatchExpression:
+			WalkDeclarations(node.Subject, declare)
+			return false
+		case *TryExpression:
+			WalkDeclarations(node.Block, declare)
+
+			if node.Finally != nil {
+				WalkDeclarations(node.Finally, declare)
+			}
+
+			return false
+		}
+
+		return true
+	})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/optimizer"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+)
+
+// Runs a script repeatedly and reports how long parsing and each run took and
+// how much memory the runs allocated. Imported modules are only evaluated by
+// the first run, as they are cached.
+func bench(args []string) {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	runs := flags.Int("n", 10, "number of runs")
+	optimize := flags.Bool("optimize", true, "optimize the program before running it")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || *runs < 1 {
+		io.WriteString(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	start := time.Now()
+	_, program := parseFile(flags.Arg(0))
+
+	if *optimize {
+		optimizer.Optimize(program)
+	}
+
+	resolver.Resolve(program)
+	parseTime := time.Since(start)
+	durations := make([]time.Duration, *runs)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	for i := range durations {
+		env := object.NewEnvironment()
+		evaluator.SetFile(env, flags.Arg(0))
+		start := time.Now()
+		result := evaluator.Eval(program, env)
+		durations[i] = time.Since(start)
+
+		if errObj, ok := result.(*object.Error); ok {
+			fmt.Fprintln(os.Stderr, errObj.Traceback())
+			os.Exit(1)
+		}
+	}
+
+	runtime.ReadMemStats(&after)
+	min, max, total := durations[0], durations[0], time.Duration(0)
+
+	for _, duration := range durations {
+		if duration < min {
+			min = duration
+		}
+
+		if duration > max {
+			max = duration
+		}
+
+		total += duration
+	}
+
+	mean := total / time.Duration(*runs)
+	count := uint64(*runs)
+	fmt.Printf("%s: %d runs\n", flags.Arg(0), *runs)
+	fmt.Printf("parse   %v\n", parseTime.Round(time.Microsecond))
+	fmt.Printf("time    min %v, mean %v, max %v\n", min.Round(time.Microsecond), mean.Round(time.Microsecond), max.Round(time.Microsecond))
+	fmt.Printf("allocs  %d per run, %d bytes per run\n", (after.Mallocs-before.Mallocs)/count, (after.TotalAlloc-before.TotalAlloc)/count)
+}
@@ -0,0 +1,35 @@
+// Package benchmarks contains the Monkey programs the interpreter is
+// benchmarked with.
+package benchmarks
+
+import (
+	"embed"
+	"sort"
+	"strings"
+)
+
+//go:embed *.monkey
+var files embed.FS
+
+type Program struct {
+	Name   string
+	Source string
+}
+
+// Programs returns the programs of the corpus sorted by name.
+func Programs() []Program {
+	entries, _ := files.ReadDir(".")
+	programs := make([]Program, 0, len(entries))
+
+	for _, entry := range entries {
+		source, _ := files.ReadFile(entry.Name())
+		name := strings.TrimSuffix(entry.Name(), ".monkey")
+		programs = append(programs, Program{Name: name, Source: string(source)})
+	}
+
+	sort.Slice(programs, func(i, j int) bool {
+		return programs[i].Name < programs[j].Name
+	})
+
+	return programs
+}
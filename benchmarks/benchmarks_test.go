@@ -0,0 +1,42 @@
+package benchmarks_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/benchmarks"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/optimizer"
+	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrograms(t *testing.T) {
+	programs := benchmarks.Programs()
+	names := []string{}
+
+	for _, program := range programs {
+		names = append(names, program.Name)
+		evaluated := testEval(t, program.Source, false)
+		assert.NotEqual(t, object.ErrorObj, evaluated.Type(), evaluated.Inspect())
+		assert.Equal(t, evaluated.Inspect(), testEval(t, program.Source, true).Inspect(), program.Name)
+	}
+
+	assert.Equal(t, []string{"arrays", "closures", "environments", "fibonacci", "strings"}, names)
+}
+
+func testEval(t *testing.T, input string, optimize bool) object.Object {
+	par := parser.NewParser(lexer.NewLexer(input))
+	program := par.ParseProgram()
+	assert.Empty(t, par.Errors())
+	assert.Empty(t, par.Warnings())
+
+	if optimize {
+		optimizer.Optimize(program)
+	}
+
+	resolver.Resolve(program)
+	return evaluator.Eval(program, object.NewEnvironment())
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/henningstorck/monkey-interpreter/typecheck"
+)
+
+// Checks the types of the given scripts without running them
+func check(args []string) {
+	if len(args) == 0 {
+		io.WriteString(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	failed := false
+
+	for _, path := range args {
+		_, program := parseFile(path)
+
+		for _, err := range typecheck.Check(program) {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", path, err)
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,90 @@
+// Package coverage describes which statements and branches of Monkey programs
+// were executed, as recorded by the evaluator, and writes it as a summary, an
+// annotated source listing or in the LCOV format.
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+// Statement counts how often a statement was evaluated.
+type Statement struct {
+	Position token.Position
+	Count    int64
+}
+
+// Branch counts how often the consequence and the alternative of an if
+// expression were taken. The alternative of an if without else is taken when
+// its condition is falsy.
+type Branch struct {
+	Position    token.Position
+	Consequence int64
+	Alternative int64
+}
+
+// File holds the statements and branches of a file in source order.
+type File struct {
+	Path       string
+	Statements []Statement
+	Branches   []Branch
+}
+
+// Profile holds the files sorted by their path.
+type Profile struct {
+	Files []*File
+}
+
+// StatementsCovered returns how many statements were evaluated at least once.
+func (file *File) StatementsCovered() (covered, total int) {
+	for _, stmt := range file.Statements {
+		if stmt.Count > 0 {
+			covered++
+		}
+	}
+
+	return covered, len(file.Statements)
+}
+
+// BranchesCovered returns how many branches were taken at least once. Each if
+// expression has two of them.
+func (file *File) BranchesCovered() (covered, total int) {
+	for _, branch := range file.Branches {
+		if branch.Consequence > 0 {
+			covered++
+		}
+
+		if branch.Alternative > 0 {
+			covered++
+		}
+	}
+
+	return covered, 2 * len(file.Branches)
+}
+
+// Lines returns the highest count of the statements starting on each line.
+func (file *File) Lines() map[int]int64 {
+	lines := map[int]int64{}
+
+	for _, stmt := range file.Statements {
+		if count, ok := lines[stmt.Position.Line]; !ok || stmt.Count > count {
+			lines[stmt.Position.Line] = stmt.Count
+		}
+	}
+
+	return lines
+}
+
+// Files in the working directory are shown relative to it
+func displayPath(path string) string {
+	if dir, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+
+	return path
+}
@@ -0,0 +1,112 @@
+package coverage_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/coverage"
+	"github.com/henningstorck/monkey-interpreter/token"
+	"github.com/stretchr/testify/assert"
+)
+
+const source = `let sign = fn(n) {
+	if (n < 0) { -1 } else { 1 }
+};
+let unused = fn() {
+	1
+};
+sign(7)
+`
+
+func testProfile(path string) *coverage.Profile {
+	return &coverage.Profile{Files: []*coverage.File{{
+		Path: path,
+		Statements: []coverage.Statement{
+			{Position: token.Position{Line: 1, Column: 1}, Count: 1},
+			{Position: token.Position{Line: 2, Column: 2}, Count: 2},
+			{Position: token.Position{Line: 2, Column: 15}, Count: 0},
+			{Position: token.Position{Line: 2, Column: 27}, Count: 2},
+			{Position: token.Position{Line: 4, Column: 1}, Count: 1},
+			{Position: token.Position{Line: 5, Column: 2}, Count: 0},
+			{Position: token.Position{Line: 7, Column: 1}, Count: 1},
+		},
+		Branches: []coverage.Branch{
+			{Position: token.Position{Line: 2, Column: 2}, Consequence: 0, Alternative: 2},
+		},
+	}}}
+}
+
+func TestCovered(t *testing.T) {
+	file := testProfile("/tmp/sign.monkey").Files[0]
+	covered, total := file.StatementsCovered()
+	assert.Equal(t, 5, covered)
+	assert.Equal(t, 7, total)
+	covered, total = file.BranchesCovered()
+	assert.Equal(t, 1, covered)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, map[int]int64{1: 1, 2: 2, 4: 1, 5: 0, 7: 1}, file.Lines())
+}
+
+func TestWriteSummary(t *testing.T) {
+	prof := testProfile("/tmp/sign.monkey")
+	prof.Files = append(prof.Files, &coverage.File{Path: "/tmp/empty.monkey"})
+	var out strings.Builder
+	prof.WriteSummary(&out)
+
+	expected := `/tmp/sign.monkey: 71.4% of statements (5/7), 50.0% of branches (1/2)
+/tmp/empty.monkey: no statements, no branches
+total: 71.4% of statements (5/7), 50.0% of branches (1/2)
+`
+
+	assert.Equal(t, expected, out.String())
+}
+
+func TestWriteListing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sign.monkey")
+	assert.Nil(t, os.WriteFile(path, []byte(source), 0o644))
+	var out strings.Builder
+	assert.Nil(t, testProfile(path).WriteListing(&out))
+
+	expected := path + `: 71.4% of statements (5/7), 50.0% of branches (1/2)
+       1 | let sign = fn(n) {
+       2 | 	if (n < 0) { -1 } else { 1 }
+         | 	^ consequence never taken
+         | };
+       1 | let unused = fn() {
+   ##### | 	1
+         | };
+       1 | sign(7)
+`
+
+	assert.Equal(t, expected, out.String())
+}
+
+func TestWriteListingMissingFile(t *testing.T) {
+	var out strings.Builder
+	assert.NotNil(t, testProfile(filepath.Join(t.TempDir(), "missing.monkey")).WriteListing(&out))
+}
+
+func TestWriteLCOV(t *testing.T) {
+	var out strings.Builder
+	assert.Nil(t, testProfile("/tmp/sign.monkey").WriteLCOV(&out))
+
+	expected := `TN:
+SF:/tmp/sign.monkey
+BRDA:2,0,0,0
+BRDA:2,0,1,2
+BRF:2
+BRH:1
+DA:1,1
+DA:2,2
+DA:4,1
+DA:5,0
+DA:7,1
+LF:5
+LH:4
+end_of_record
+`
+
+	assert.Equal(t, expected, out.String())
+}
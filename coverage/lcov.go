@@ -0,0 +1,62 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WriteLCOV writes the profile in the tracefile format of LCOV, which most
+// coverage tools can import. Each if expression is a block with the branches
+// 0 for its consequence and 1 for its alternative.
+func (prof *Profile) WriteLCOV(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	for _, file := range prof.Files {
+		fmt.Fprintln(out, "TN:")
+		fmt.Fprintf(out, "SF:%s\n", file.Path)
+		hit := 0
+
+		for i, branch := range file.Branches {
+			for j, taken := range []int64{branch.Consequence, branch.Alternative} {
+				if taken > 0 {
+					hit++
+				}
+
+				// Branches of ifs which were never evaluated are reported as -
+				if branch.Consequence == 0 && branch.Alternative == 0 {
+					fmt.Fprintf(out, "BRDA:%d,%d,%d,-\n", branch.Position.Line, i, j)
+				} else {
+					fmt.Fprintf(out, "BRDA:%d,%d,%d,%d\n", branch.Position.Line, i, j, taken)
+				}
+			}
+		}
+
+		fmt.Fprintf(out, "BRF:%d\n", 2*len(file.Branches))
+		fmt.Fprintf(out, "BRH:%d\n", hit)
+		counts := file.Lines()
+		lines := make([]int, 0, len(counts))
+		hit = 0
+
+		for line, count := range counts {
+			lines = append(lines, line)
+
+			if count > 0 {
+				hit++
+			}
+		}
+
+		sort.Ints(lines)
+
+		for _, line := range lines {
+			fmt.Fprintf(out, "DA:%d,%d\n", line, counts[line])
+		}
+
+		fmt.Fprintf(out, "LF:%d\n", len(lines))
+		fmt.Fprintf(out, "LH:%d\n", hit)
+		fmt.Fprintln(out, "end_of_record")
+	}
+
+	return out.Flush()
+}
@@ -0,0 +1,128 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// WriteSummary writes the percentage of statements and branches covered for
+// each file and for all of them.
+func (prof *Profile) WriteSummary(w io.Writer) {
+	total := &File{}
+
+	for _, file := range prof.Files {
+		fmt.Fprintf(w, "%s: %s\n", displayPath(file.Path), summary(file))
+		total.Statements = append(total.Statements, file.Statements...)
+		total.Branches = append(total.Branches, file.Branches...)
+	}
+
+	fmt.Fprintf(w, "total: %s\n", summary(total))
+}
+
+func summary(file *File) string {
+	covered, total := file.StatementsCovered()
+	out := ratio(covered, total, "statements")
+	covered, total = file.BranchesCovered()
+	return out + ", " + ratio(covered, total, "branches")
+}
+
+func ratio(covered, total int, what string) string {
+	if total == 0 {
+		return "no " + what
+	}
+
+	return fmt.Sprintf("%.1f%% of %s (%d/%d)", float64(covered)*100/float64(total), what, covered, total)
+}
+
+// WriteListing writes the source of each file with the number of times each
+// line was evaluated in front of it. Lines which were never evaluated are
+// marked with #####, and if expressions with a branch that was never taken are
+// pointed out below their line.
+func (prof *Profile) WriteListing(w io.Writer) error {
+	for i, file := range prof.Files {
+		source, err := os.ReadFile(file.Path)
+
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "%s: %s\n", displayPath(file.Path), summary(file))
+		file.writeListing(w, string(source))
+	}
+
+	return nil
+}
+
+func (file *File) writeListing(w io.Writer, source string) {
+	counts := file.Lines()
+	branches := map[int][]Branch{}
+
+	for _, branch := range file.Branches {
+		if branch.Consequence == 0 || branch.Alternative == 0 {
+			branches[branch.Position.Line] = append(branches[branch.Position.Line], branch)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(source, "\n"), "\n")
+
+	for i, line := range lines {
+		count, ok := counts[i+1]
+		prefix := ""
+
+		if ok && count == 0 {
+			prefix = "#####"
+		} else if ok {
+			prefix = fmt.Sprint(count)
+		}
+
+		fmt.Fprintf(w, "%8s | %s\n", prefix, line)
+		missed := branches[i+1]
+
+		sort.Slice(missed, func(i, j int) bool {
+			return missed[i].Position.Column < missed[j].Position.Column
+		})
+
+		for _, branch := range missed {
+			fmt.Fprintf(w, "%8s | %s^ %s\n", "", indent(line, branch.Position.Column), untaken(branch))
+		}
+	}
+}
+
+// Keeps the tabs in front of the column so the marker lines up
+func indent(line string, column int) string {
+	runes := []rune(line)
+
+	if column < 1 {
+		column = 1
+	}
+
+	if column-1 < len(runes) {
+		runes = runes[:column-1]
+	}
+
+	for i, r := range runes {
+		if r != '\t' {
+			runes[i] = ' '
+		}
+	}
+
+	return string(runes)
+}
+
+func untaken(branch Branch) string {
+	switch {
+	case branch.Consequence == 0 && branch.Alternative == 0:
+		return "never evaluated"
+	case branch.Consequence == 0:
+		return "consequence never taken"
+	default:
+		return "alternative never taken"
+	}
+}
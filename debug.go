@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/henningstorck/monkey-interpreter/debugger"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+)
+
+func debug(args []string) {
+	flags := flag.NewFlagSet("debug", flag.ExitOnError)
+	dap := flags.Bool("dap", false, "serve the Debug Adapter Protocol on stdin and stdout")
+	flags.Parse(args)
+
+	if *dap {
+		if err := debugger.NewDAP(os.Stdin, os.Stdout).Serve(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if flags.NArg() != 1 {
+		io.WriteString(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	source, program := parseFile(flags.Arg(0))
+	resolver.Resolve(program)
+	cli := debugger.NewCLI(os.Stdin, os.Stdout, flags.Arg(0), source)
+	env := object.NewEnvironment()
+	evaluator.SetFile(env, flags.Arg(0))
+	result := cli.Run(program, env)
+
+	if errObj, ok := result.(*object.Error); ok {
+		fmt.Fprintln(os.Stderr, errObj.Traceback())
+		os.Exit(1)
+	}
+
+	if result != nil {
+		fmt.Println(result.Inspect())
+	}
+}
@@ -0,0 +1,280 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+const prompt = "(debug) "
+
+const help = `Commands:
+	break <line>, b <line>   set a breakpoint, use <file>:<line> for other files
+	delete <line>, d <line>  remove a breakpoint
+	breakpoints              list all breakpoints
+	continue, c              run until the next breakpoint
+	step, s                  step into the next statement
+	next, n                  step over function calls
+	out, o                   step out of the current function
+	stack, bt                show the call stack
+	frame <n>, f <n>         select a frame of the call stack
+	locals, l                show the bindings of the selected frame
+	scopes                   show the bindings of all enclosing environments
+	print <name>, p <name>   show the value of a variable
+	list                     show the source around the current line
+	quit, q                  stop debugging
+`
+
+// CLI is an interactive, line based debugger frontend.
+type CLI struct {
+	Debugger *Debugger
+
+	scanner *bufio.Scanner
+	out     io.Writer
+	path    string
+	sources map[string][]string
+	frame   int
+}
+
+// NewCLI creates a frontend for the script at the given path. The sources of
+// imported modules are read when the debugger stops in them.
+func NewCLI(in io.Reader, out io.Writer, path, source string) *CLI {
+	path = absPath(path)
+
+	cli := &CLI{
+		scanner: bufio.NewScanner(in),
+		out:     out,
+		path:    path,
+		sources: map[string][]string{path: strings.Split(source, "\n")},
+	}
+
+	cli.Debugger = NewDebugger(cli, true)
+	return cli
+}
+
+func (cli *CLI) Run(program *ast.Program, env *object.Environment) object.Object {
+	return cli.Debugger.Run(program, env)
+}
+
+func (cli *CLI) Stopped(stop *Stop) Action {
+	cli.frame = 0
+	frame := stop.Frames[0]
+	fmt.Fprintf(cli.out, "Stopped (%s) in %s at %s\n", stop.Reason, frame.Function, cli.location(frame.File, frame.Line))
+	cli.printLine(frame.File, frame.Line)
+
+	for {
+		fmt.Fprint(cli.out, prompt)
+
+		if !cli.scanner.Scan() {
+			return Quit
+		}
+
+		fields := strings.Fields(cli.scanner.Text())
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		if action, ok := cli.execute(stop, fields[0], fields[1:]); ok {
+			return action
+		}
+	}
+}
+
+func (cli *CLI) execute(stop *Stop, command string, args []string) (Action, bool) {
+	switch command {
+	case "continue", "c":
+		return Continue, true
+	case "step", "s":
+		return StepIn, true
+	case "next", "n":
+		return StepOver, true
+	case "out", "o":
+		return StepOut, true
+	case "quit", "q":
+		return Quit, true
+	case "break", "b":
+		if file, line, ok := cli.lineArgument(args); ok {
+			cli.Debugger.SetBreakpoint(file, line)
+			fmt.Fprintf(cli.out, "Breakpoint set at %s\n", cli.location(file, line))
+		}
+	case "delete", "d":
+		if file, line, ok := cli.lineArgument(args); ok {
+			cli.Debugger.ClearBreakpoint(file, line)
+			fmt.Fprintf(cli.out, "Breakpoint removed at %s\n", cli.location(file, line))
+		}
+	case "breakpoints":
+		for _, breakpoint := range cli.Debugger.Breakpoints() {
+			fmt.Fprintln(cli.out, cli.location(breakpoint.File, breakpoint.Line))
+		}
+	case "stack", "bt":
+		cli.printStack(stop)
+	case "frame", "f":
+		cli.selectFrame(stop, args)
+	case "locals", "l":
+		cli.printBindings(stop.Frames[cli.frame].Env)
+	case "scopes":
+		cli.printScopes(stop.Frames[cli.frame].Env)
+	case "print", "p":
+		cli.printVariable(stop.Frames[cli.frame].Env, args)
+	case "list":
+		cli.printSource(stop.Frames[cli.frame].File, stop.Frames[cli.frame].Line)
+	case "help", "h":
+		io.WriteString(cli.out, help)
+	default:
+		fmt.Fprintf(cli.out, "unknown command: %s\n", command)
+	}
+
+	return Continue, false
+}
+
+// Lines without a file refer to the debugged script
+func (cli *CLI) lineArgument(args []string) (string, int, bool) {
+	if len(args) != 1 {
+		io.WriteString(cli.out, "expected a line number\n")
+		return "", 0, false
+	}
+
+	file, value := cli.path, args[0]
+
+	if i := strings.LastIndex(value, ":"); i >= 0 {
+		file, value = absPath(value[:i]), value[i+1:]
+	}
+
+	line, err := strconv.Atoi(value)
+
+	if err != nil || line < 1 {
+		fmt.Fprintf(cli.out, "invalid line number: %s\n", args[0])
+		return "", 0, false
+	}
+
+	return file, line, true
+}
+
+// Lines of modules are shown with their path relative to the debugged script
+func (cli *CLI) location(file string, line int) string {
+	if file == cli.path {
+		return fmt.Sprintf("line %d", line)
+	}
+
+	if rel, err := filepath.Rel(filepath.Dir(cli.path), file); err == nil {
+		file = rel
+	}
+
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+func (cli *CLI) lines(file string) []string {
+	if lines, ok := cli.sources[file]; ok {
+		return lines
+	}
+
+	var lines []string
+
+	if source, err := os.ReadFile(file); err == nil {
+		lines = strings.Split(string(source), "\n")
+	}
+
+	cli.sources[file] = lines
+	return lines
+}
+
+func (cli *CLI) printStack(stop *Stop) {
+	for i, frame := range stop.Frames {
+		marker := " "
+
+		if i == cli.frame {
+			marker = "*"
+		}
+
+		fmt.Fprintf(cli.out, "%s #%d %s at %s, column %d\n", marker, i, frame.Function, cli.location(frame.File, frame.Line), frame.Column)
+	}
+}
+
+func (cli *CLI) selectFrame(stop *Stop, args []string) {
+	if len(args) != 1 {
+		io.WriteString(cli.out, "expected a frame number\n")
+		return
+	}
+
+	index, err := strconv.Atoi(args[0])
+
+	if err != nil || index < 0 || index >= len(stop.Frames) {
+		fmt.Fprintf(cli.out, "invalid frame number: %s\n", args[0])
+		return
+	}
+
+	cli.frame = index
+	frame := stop.Frames[index]
+	fmt.Fprintf(cli.out, "#%d %s at %s\n", index, frame.Function, cli.location(frame.File, frame.Line))
+	cli.printLine(frame.File, frame.Line)
+}
+
+func (cli *CLI) printBindings(env *object.Environment) {
+	for _, name := range env.Names() {
+		value, _ := env.Get(name)
+		fmt.Fprintf(cli.out, "%s = %s\n", name, value.Inspect())
+	}
+}
+
+func (cli *CLI) printScopes(env *object.Environment) {
+	for depth := 0; env != nil; depth++ {
+		fmt.Fprintf(cli.out, "scope %d:\n", depth)
+
+		for _, name := range env.Names() {
+			value, _ := env.Get(name)
+			fmt.Fprintf(cli.out, "\t%s = %s\n", name, value.Inspect())
+		}
+
+		env = env.Outer()
+	}
+}
+
+func (cli *CLI) printVariable(env *object.Environment, args []string) {
+	if len(args) != 1 {
+		io.WriteString(cli.out, "expected a variable name\n")
+		return
+	}
+
+	value, ok := env.Get(args[0])
+
+	if !ok {
+		fmt.Fprintf(cli.out, "identifier not found: %s\n", args[0])
+		return
+	}
+
+	fmt.Fprintf(cli.out, "%s = %s\n", args[0], value.Inspect())
+}
+
+func (cli *CLI) printLine(file string, line int) {
+	source := cli.lines(file)
+
+	if line >= 1 && line <= len(source) {
+		fmt.Fprintf(cli.out, "%4d\t%s\n", line, source[line-1])
+	}
+}
+
+func (cli *CLI) printSource(file string, current int) {
+	source := cli.lines(file)
+
+	for line := current - 3; line <= current+3; line++ {
+		if line < 1 || line > len(source) {
+			continue
+		}
+
+		marker := " "
+
+		if line == current {
+			marker = ">"
+		}
+
+		fmt.Fprintf(cli.out, "%s%4d\t%s\n", marker, line, source[line-1])
+	}
+}
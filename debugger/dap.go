@@ -0,0 +1,433 @@
+package debugger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+)
+
+const threadID = 1
+
+type dapMessage struct {
+	Seq        int             `json:"seq"`
+	Type       string          `json:"type"`
+	Command    string          `json:"command,omitempty"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	RequestSeq int             `json:"request_seq,omitempty"`
+	Success    *bool           `json:"success,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	Event      string          `json:"event,omitempty"`
+	Body       any             `json:"body,omitempty"`
+}
+
+type dapSource struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type dapStackFrame struct {
+	ID     int       `json:"id"`
+	Name   string    `json:"name"`
+	Source dapSource `json:"source"`
+	Line   int       `json:"line"`
+	Column int       `json:"column"`
+}
+
+type dapScope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+type dapVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// DAP serves the Debug Adapter Protocol, so editors are able to drive the
+// debugger.
+type DAP struct {
+	reader    *bufio.Reader
+	writer    io.Writer
+	writeLock sync.Mutex
+	seq       int
+	lock      sync.Mutex
+
+	debugger  *Debugger
+	path      string
+	source    string
+	actions   chan Action
+	stop      *Stop
+	variables map[int]*object.Environment
+}
+
+func NewDAP(in io.Reader, out io.Writer) *DAP {
+	dap := &DAP{
+		reader:  bufio.NewReader(in),
+		writer:  out,
+		actions: make(chan Action),
+	}
+
+	dap.debugger = NewDebugger(dap, false)
+	return dap
+}
+
+// Serve handles requests until the client disconnects.
+func (dap *DAP) Serve() error {
+	for {
+		msg, err := dap.read()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if msg.Type != "request" {
+			continue
+		}
+
+		if !dap.handle(msg) {
+			return nil
+		}
+	}
+}
+
+func (dap *DAP) Stopped(stop *Stop) Action {
+	dap.lock.Lock()
+	dap.stop = stop
+	dap.variables = make(map[int]*object.Environment)
+	dap.lock.Unlock()
+
+	dap.sendEvent("stopped", map[string]any{
+		"reason":            stop.Reason,
+		"threadId":          threadID,
+		"allThreadsStopped": true,
+	})
+
+	action := <-dap.actions
+
+	dap.lock.Lock()
+	dap.stop = nil
+	dap.lock.Unlock()
+
+	return action
+}
+
+func (dap *DAP) handle(msg *dapMessage) bool {
+	switch msg.Command {
+	case "initialize":
+		dap.respond(msg, map[string]any{"supportsConfigurationDoneRequest": true})
+		dap.sendEvent("initialized", nil)
+	case "launch":
+		dap.launch(msg)
+	case "setBreakpoints":
+		dap.setBreakpoints(msg)
+	case "configurationDone":
+		dap.respond(msg, nil)
+		go dap.run()
+	case "threads":
+		dap.respond(msg, map[string]any{
+			"threads": []map[string]any{{"id": threadID, "name": "main"}},
+		})
+	case "stackTrace":
+		dap.stackTrace(msg)
+	case "scopes":
+		dap.scopes(msg)
+	case "variables":
+		dap.variablesOf(msg)
+	case "continue":
+		dap.respond(msg, map[string]any{"allThreadsContinued": true})
+		dap.resume(Continue)
+	case "next":
+		dap.respond(msg, nil)
+		dap.resume(StepOver)
+	case "stepIn":
+		dap.respond(msg, nil)
+		dap.resume(StepIn)
+	case "stepOut":
+		dap.respond(msg, nil)
+		dap.resume(StepOut)
+	case "disconnect", "terminate":
+		dap.respond(msg, nil)
+		dap.resume(Quit)
+		return false
+	default:
+		dap.fail(msg, fmt.Sprintf("unsupported request: %s", msg.Command))
+	}
+
+	return true
+}
+
+func (dap *DAP) launch(msg *dapMessage) {
+	var args struct {
+		Program     string `json:"program"`
+		StopOnEntry bool   `json:"stopOnEntry"`
+	}
+
+	if err := json.Unmarshal(msg.Arguments, &args); err != nil {
+		dap.fail(msg, err.Error())
+		return
+	}
+
+	source, err := os.ReadFile(args.Program)
+
+	if err != nil {
+		dap.fail(msg, err.Error())
+		return
+	}
+
+	dap.path = args.Program
+	dap.source = string(source)
+
+	if args.StopOnEntry {
+		dap.debugger.action = StepIn
+	}
+
+	dap.respond(msg, nil)
+}
+
+func (dap *DAP) setBreakpoints(msg *dapMessage) {
+	var args struct {
+		Source      dapSource `json:"source"`
+		Breakpoints []struct {
+			Line int `json:"line"`
+		} `json:"breakpoints"`
+	}
+
+	if err := json.Unmarshal(msg.Arguments, &args); err != nil {
+		dap.fail(msg, err.Error())
+		return
+	}
+
+	dap.debugger.ClearBreakpoints(args.Source.Path)
+	breakpoints := []map[string]any{}
+
+	for _, breakpoint := range args.Breakpoints {
+		dap.debugger.SetBreakpoint(args.Source.Path, breakpoint.Line)
+		breakpoints = append(breakpoints, map[string]any{"verified": true, "line": breakpoint.Line})
+	}
+
+	dap.respond(msg, map[string]any{"breakpoints": breakpoints})
+}
+
+func (dap *DAP) stackTrace(msg *dapMessage) {
+	dap.lock.Lock()
+	defer dap.lock.Unlock()
+
+	frames := []dapStackFrame{}
+
+	if dap.stop != nil {
+		for i, frame := range dap.stop.Frames {
+			frames = append(frames, dapStackFrame{
+				ID:     i,
+				Name:   frame.Function,
+				Source: dapSource{Name: filepath.Base(frame.File), Path: frame.File},
+				Line:   frame.Line,
+				Column: frame.Column,
+			})
+		}
+	}
+
+	dap.respond(msg, map[string]any{"stackFrames": frames, "totalFrames": len(frames)})
+}
+
+func (dap *DAP) scopes(msg *dapMessage) {
+	var args struct {
+		FrameID int `json:"frameId"`
+	}
+
+	if err := json.Unmarshal(msg.Arguments, &args); err != nil {
+		dap.fail(msg, err.Error())
+		return
+	}
+
+	dap.lock.Lock()
+	defer dap.lock.Unlock()
+
+	scopes := []dapScope{}
+
+	if dap.stop != nil && args.FrameID >= 0 && args.FrameID < len(dap.stop.Frames) {
+		env := dap.stop.Frames[args.FrameID].Env
+
+		for depth := 0; env != nil; depth++ {
+			name := "Locals"
+
+			if env.Outer() == nil {
+				name = "Globals"
+			} else if depth > 0 {
+				name = "Closure " + strconv.Itoa(depth)
+			}
+
+			reference := len(dap.variables) + 1
+			dap.variables[reference] = env
+			scopes = append(scopes, dapScope{Name: name, VariablesReference: reference})
+			env = env.Outer()
+		}
+	}
+
+	dap.respond(msg, map[string]any{"scopes": scopes})
+}
+
+func (dap *DAP) variablesOf(msg *dapMessage) {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+
+	if err := json.Unmarshal(msg.Arguments, &args); err != nil {
+		dap.fail(msg, err.Error())
+		return
+	}
+
+	dap.lock.Lock()
+	defer dap.lock.Unlock()
+
+	variables := []dapVariable{}
+
+	if env, ok := dap.variables[args.VariablesReference]; ok && dap.stop != nil {
+		for _, name := range env.Names() {
+			value, _ := env.Get(name)
+			variables = append(variables, dapVariable{
+				Name:  name,
+				Value: value.Inspect(),
+				Type:  string(value.Type()),
+			})
+		}
+	}
+
+	dap.respond(msg, map[string]any{"variables": variables})
+}
+
+func (dap *DAP) resume(action Action) {
+	dap.lock.Lock()
+	stopped := dap.stop != nil
+	dap.lock.Unlock()
+
+	if action == Quit {
+		dap.debugger.Terminate()
+	}
+
+	if stopped {
+		dap.actions <- action
+	}
+}
+
+func (dap *DAP) run() {
+	lex := lexer.NewLexer(dap.source)
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+
+	if len(par.Errors()) != 0 {
+		for _, msg := range par.Errors() {
+			dap.sendOutput("stderr", msg+"\n")
+		}
+
+		dap.sendEvent("terminated", nil)
+		return
+	}
+
+	for _, msg := range par.Warnings() {
+		dap.sendOutput("console", "warning: "+msg+"\n")
+	}
+
+	resolver.Resolve(program)
+	env := object.NewEnvironment()
+	evaluator.SetFile(env, dap.path)
+	result := dap.debugger.Run(program, env)
+	exitCode := 0
+
+	if errObj, ok := result.(*object.Error); ok {
+		dap.sendOutput("stderr", errObj.Traceback()+"\n")
+		exitCode = 1
+	} else if result != nil {
+		dap.sendOutput("stdout", result.Inspect()+"\n")
+	}
+
+	dap.sendEvent("exited", map[string]any{"exitCode": exitCode})
+	dap.sendEvent("terminated", nil)
+}
+
+func (dap *DAP) read() (*dapMessage, error) {
+	length := -1
+
+	for {
+		line, err := dap.reader.ReadString('\n')
+
+		if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			break
+		}
+
+		if strings.HasPrefix(line, "Content-Length:") {
+			value := strings.TrimPrefix(line, "Content-Length:")
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	content := make([]byte, length)
+
+	if _, err := io.ReadFull(dap.reader, content); err != nil {
+		return nil, err
+	}
+
+	msg := &dapMessage{}
+	err := json.Unmarshal(content, msg)
+	return msg, err
+}
+
+func (dap *DAP) respond(request *dapMessage, body any) {
+	success := true
+	dap.send(&dapMessage{Type: "response", RequestSeq: request.Seq, Command: request.Command, Success: &success, Body: body})
+}
+
+func (dap *DAP) fail(request *dapMessage, message string) {
+	success := false
+	dap.send(&dapMessage{Type: "response", RequestSeq: request.Seq, Command: request.Command, Success: &success, Message: message})
+}
+
+func (dap *DAP) sendEvent(event string, body any) {
+	dap.send(&dapMessage{Type: "event", Event: event, Body: body})
+}
+
+func (dap *DAP) sendOutput(category, output string) {
+	dap.sendEvent("output", map[string]any{"category": category, "output": output})
+}
+
+func (dap *DAP) send(msg *dapMessage) {
+	dap.writeLock.Lock()
+	defer dap.writeLock.Unlock()
+
+	dap.seq++
+	msg.Seq = dap.seq
+	content, _ := json.Marshal(msg)
+	fmt.Fprintf(dap.writer, "Content-Length: %d\r\n\r\n%s", len(content), content)
+}
@@ -0,0 +1,215 @@
+package debugger_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/debugger"
+	"github.com/stretchr/testify/assert"
+)
+
+type dapClient struct {
+	t      *testing.T
+	writer io.Writer
+	reader *bufio.Reader
+	seq    int
+}
+
+func (client *dapClient) send(command string, arguments any) {
+	client.seq++
+	content, _ := json.Marshal(map[string]any{
+		"seq":       client.seq,
+		"type":      "request",
+		"command":   command,
+		"arguments": arguments,
+	})
+
+	fmt.Fprintf(client.writer, "Content-Length: %d\r\n\r\n%s", len(content), content)
+}
+
+func (client *dapClient) receive() map[string]any {
+	length := 0
+
+	for {
+		line, err := client.reader.ReadString('\n')
+		assert.NoError(client.t, err)
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			break
+		}
+
+		length, _ = strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "Content-Length:")))
+	}
+
+	content := make([]byte, length)
+	_, err := io.ReadFull(client.reader, content)
+	assert.NoError(client.t, err)
+
+	msg := map[string]any{}
+	assert.NoError(client.t, json.Unmarshal(content, &msg))
+	return msg
+}
+
+func (client *dapClient) expect(kind, name string) map[string]any {
+	msg := client.receive()
+	assert.Equal(client.t, kind, msg["type"])
+
+	if kind == "event" {
+		assert.Equal(client.t, name, msg["event"])
+	} else {
+		assert.Equal(client.t, name, msg["command"])
+		assert.Equal(client.t, true, msg["success"])
+	}
+
+	body, _ := msg["body"].(map[string]any)
+	return body
+}
+
+func TestDAP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "add.monkey")
+	assert.NoError(t, os.WriteFile(path, []byte(input), 0o644))
+
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	done := make(chan error)
+
+	go func() {
+		done <- debugger.NewDAP(serverIn, serverOut).Serve()
+	}()
+
+	client := &dapClient{t: t, writer: clientOut, reader: bufio.NewReader(clientIn)}
+
+	client.send("initialize", map[string]any{"adapterID": "monkey"})
+	client.expect("response", "initialize")
+	client.expect("event", "initialized")
+
+	client.send("launch", map[string]any{"program": path})
+	client.expect("response", "launch")
+
+	client.send("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": path},
+		"breakpoints": []map[string]any{{"line": 2}},
+	})
+
+	body := client.expect("response", "setBreakpoints")
+	assert.Len(t, body["breakpoints"], 1)
+
+	client.send("configurationDone", nil)
+	client.expect("response", "configurationDone")
+	body = client.expect("event", "stopped")
+	assert.Equal(t, "breakpoint", body["reason"])
+
+	client.send("stackTrace", map[string]any{"threadId": 1})
+	body = client.expect("response", "stackTrace")
+	frames := body["stackFrames"].([]any)
+	assert.Len(t, frames, 2)
+	assert.Equal(t, "add", frames[0].(map[string]any)["name"])
+	assert.Equal(t, float64(2), frames[0].(map[string]any)["line"])
+
+	client.send("scopes", map[string]any{"frameId": 0})
+	body = client.expect("response", "scopes")
+	scopes := body["scopes"].([]any)
+	assert.Len(t, scopes, 2)
+	locals := scopes[0].(map[string]any)
+	assert.Equal(t, "Locals", locals["name"])
+
+	client.send("variables", map[string]any{"variablesReference": locals["variablesReference"]})
+	body = client.expect("response", "variables")
+	variables := body["variables"].([]any)
+	assert.Len(t, variables, 2)
+	assert.Equal(t, "a", variables[0].(map[string]any)["name"])
+	assert.Equal(t, "1", variables[0].(map[string]any)["value"])
+
+	client.send("stepOut", map[string]any{"threadId": 1})
+	client.expect("response", "stepOut")
+	body = client.expect("event", "stopped")
+	assert.Equal(t, "step", body["reason"])
+
+	client.send("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": path},
+		"breakpoints": []map[string]any{},
+	})
+
+	client.expect("response", "setBreakpoints")
+	client.send("continue", map[string]any{"threadId": 1})
+	client.expect("response", "continue")
+	body = client.expect("event", "output")
+	assert.Equal(t, "6\n", body["output"])
+	body = client.expect("event", "exited")
+	assert.Equal(t, float64(0), body["exitCode"])
+	client.expect("event", "terminated")
+
+	client.send("disconnect", nil)
+	client.expect("response", "disconnect")
+	assert.NoError(t, <-done)
+}
+
+func TestDAPModules(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "main.monkey")
+	libPath := filepath.Join(dir, "lib.monkey")
+	assert.NoError(t, os.WriteFile(mainPath, []byte("let lib = import \"lib\";\nlet x = lib.double(2);\nx;"), 0o644))
+	assert.NoError(t, os.WriteFile(libPath, []byte("export let double = fn(x) {\n\tlet y = x * 2;\n\ty;\n};"), 0o644))
+
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	done := make(chan error)
+
+	go func() {
+		done <- debugger.NewDAP(serverIn, serverOut).Serve()
+	}()
+
+	client := &dapClient{t: t, writer: clientOut, reader: bufio.NewReader(clientIn)}
+
+	client.send("initialize", map[string]any{"adapterID": "monkey"})
+	client.expect("response", "initialize")
+	client.expect("event", "initialized")
+
+	client.send("launch", map[string]any{"program": mainPath})
+	client.expect("response", "launch")
+
+	client.send("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": libPath},
+		"breakpoints": []map[string]any{{"line": 2}},
+	})
+
+	client.expect("response", "setBreakpoints")
+
+	client.send("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": mainPath},
+		"breakpoints": []map[string]any{},
+	})
+
+	client.expect("response", "setBreakpoints")
+	client.send("configurationDone", nil)
+	client.expect("response", "configurationDone")
+	body := client.expect("event", "stopped")
+	assert.Equal(t, "breakpoint", body["reason"])
+
+	client.send("stackTrace", map[string]any{"threadId": 1})
+	body = client.expect("response", "stackTrace")
+	frames := body["stackFrames"].([]any)
+	assert.Len(t, frames, 2)
+	assert.Equal(t, float64(2), frames[0].(map[string]any)["line"])
+	assert.Equal(t, libPath, frames[0].(map[string]any)["source"].(map[string]any)["path"])
+	assert.Equal(t, mainPath, frames[1].(map[string]any)["source"].(map[string]any)["path"])
+
+	client.send("continue", map[string]any{"threadId": 1})
+	client.expect("response", "continue")
+	body = client.expect("event", "output")
+	assert.Equal(t, "4\n", body["output"])
+	client.expect("event", "exited")
+	client.expect("event", "terminated")
+
+	client.send("disconnect", nil)
+	client.expect("response", "disconnect")
+	assert.NoError(t, <-done)
+}
@@ -0,0 +1,242 @@
+package debugger
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+type Action int
+
+const (
+	Continue Action = iota
+	StepIn
+	StepOver
+	StepOut
+	Quit
+)
+
+const (
+	ReasonEntry      = "entry"
+	ReasonBreakpoint = "breakpoint"
+	ReasonStep       = "step"
+)
+
+// Stop describes the statement the debugger is paused at.
+type Stop struct {
+	Reason string
+	Node   ast.Node
+	Env    *object.Environment
+	Frames []Frame
+}
+
+// Frame is a single entry of the call stack, starting with the innermost call.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+	Column   int
+	Env      *object.Environment
+}
+
+// Breakpoint is a line of a file. Scripts and modules evaluated without a file
+// use an empty path.
+type Breakpoint struct {
+	File string
+	Line int
+}
+
+// Frontend decides how execution continues whenever the debugger pauses.
+type Frontend interface {
+	Stopped(stop *Stop) Action
+}
+
+type Debugger struct {
+	frontend    Frontend
+	lock        sync.Mutex
+	breakpoints map[Breakpoint]bool
+	terminated  bool
+	action      Action
+	depth       int
+	file        string
+	line        int
+	lineDepth   int
+}
+
+func NewDebugger(frontend Frontend, stopOnEntry bool) *Debugger {
+	dbg := &Debugger{
+		frontend:    frontend,
+		breakpoints: make(map[Breakpoint]bool),
+		action:      Continue,
+	}
+
+	if stopOnEntry {
+		dbg.action = StepIn
+	}
+
+	return dbg
+}
+
+func (dbg *Debugger) SetBreakpoint(file string, line int) {
+	dbg.lock.Lock()
+	defer dbg.lock.Unlock()
+	dbg.breakpoints[Breakpoint{File: absPath(file), Line: line}] = true
+}
+
+func (dbg *Debugger) ClearBreakpoint(file string, line int) {
+	dbg.lock.Lock()
+	defer dbg.lock.Unlock()
+	delete(dbg.breakpoints, Breakpoint{File: absPath(file), Line: line})
+}
+
+// ClearBreakpoints removes the breakpoints of a file, keeping those of others.
+func (dbg *Debugger) ClearBreakpoints(file string) {
+	dbg.lock.Lock()
+	defer dbg.lock.Unlock()
+	file = absPath(file)
+
+	for breakpoint := range dbg.breakpoints {
+		if breakpoint.File == file {
+			delete(dbg.breakpoints, breakpoint)
+		}
+	}
+}
+
+func (dbg *Debugger) Breakpoints() []Breakpoint {
+	dbg.lock.Lock()
+	defer dbg.lock.Unlock()
+	breakpoints := []Breakpoint{}
+
+	for breakpoint := range dbg.breakpoints {
+		breakpoints = append(breakpoints, breakpoint)
+	}
+
+	sort.Slice(breakpoints, func(i, j int) bool {
+		if breakpoints[i].File != breakpoints[j].File {
+			return breakpoints[i].File < breakpoints[j].File
+		}
+
+		return breakpoints[i].Line < breakpoints[j].Line
+	})
+
+	return breakpoints
+}
+
+// Terminate aborts the evaluation before the next statement.
+func (dbg *Debugger) Terminate() {
+	dbg.lock.Lock()
+	defer dbg.lock.Unlock()
+	dbg.terminated = true
+}
+
+// Run evaluates the program while the debugger is attached.
+func (dbg *Debugger) Run(program *ast.Program, env *object.Environment) object.Object {
+	evaluator.SetHook(func(node ast.Node, nodeEnv *object.Environment) *object.Error {
+		return dbg.hook(node, nodeEnv, env)
+	})
+
+	defer evaluator.SetHook(nil)
+	return evaluator.Eval(program, env)
+}
+
+func (dbg *Debugger) hook(node ast.Node, env, globalEnv *object.Environment) *object.Error {
+	if dbg.isTerminated() {
+		return &object.Error{Message: "debugger terminated"}
+	}
+
+	callStack := evaluator.CallStack()
+	depth := len(callStack)
+	file := evaluator.FileOf(env)
+	pos := node.Pos()
+	reason := dbg.stopReason(file, pos.Line, depth)
+	dbg.file = file
+	dbg.line = pos.Line
+	dbg.lineDepth = depth
+
+	if reason == "" {
+		return nil
+	}
+
+	frames := make([]Frame, 0, depth+1)
+	line, column := pos.Line, pos.Column
+
+	for i := depth - 1; i >= 0; i-- {
+		frames = append(frames, Frame{
+			Function: callStack[i].Function,
+			File:     file,
+			Line:     line,
+			Column:   column,
+			Env:      callStack[i].Env,
+		})
+
+		line, column = callStack[i].Position.Line, callStack[i].Position.Column
+
+		if i > 0 {
+			file = evaluator.FileOf(callStack[i-1].Env)
+		} else {
+			file = evaluator.FileOf(globalEnv)
+		}
+	}
+
+	frames = append(frames, Frame{Function: "<main>", File: file, Line: line, Column: column, Env: globalEnv})
+
+	dbg.action = dbg.frontend.Stopped(&Stop{
+		Reason: reason,
+		Node:   node,
+		Env:    env,
+		Frames: frames,
+	})
+
+	dbg.depth = depth
+
+	if dbg.action == Quit {
+		dbg.Terminate()
+		return &object.Error{Message: "debugger terminated"}
+	}
+
+	return nil
+}
+
+func (dbg *Debugger) isTerminated() bool {
+	dbg.lock.Lock()
+	defer dbg.lock.Unlock()
+	return dbg.terminated
+}
+
+func (dbg *Debugger) stopReason(file string, line, depth int) string {
+	dbg.lock.Lock()
+	defer dbg.lock.Unlock()
+
+	switch {
+	case dbg.action == StepIn && dbg.line == 0:
+		return ReasonEntry
+	case dbg.action == StepIn:
+		return ReasonStep
+	case dbg.action == StepOver && depth <= dbg.depth:
+		return ReasonStep
+	case dbg.action == StepOut && depth < dbg.depth:
+		return ReasonStep
+	case dbg.breakpoints[Breakpoint{File: file, Line: line}] && (file != dbg.file || line != dbg.line || depth != dbg.lineDepth):
+		return ReasonBreakpoint
+	default:
+		return ""
+	}
+}
+
+// Files of environments are absolute, so breakpoints are set on absolute paths
+// as well
+func absPath(file string) string {
+	if file == "" {
+		return file
+	}
+
+	if path, err := filepath.Abs(file); err == nil {
+		return path
+	}
+
+	return file
+}
@@ -0,0 +1,201 @@
+package debugger_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/debugger"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+	"github.com/stretchr/testify/assert"
+)
+
+const input = `let add = fn(a, b) {
+	let sum = a + b;
+	sum;
+};
+
+let x = add(1, 2);
+let y = add(x, 3);
+y;`
+
+type scriptedFrontend struct {
+	actions []debugger.Action
+	stops   []*debugger.Stop
+}
+
+func (frontend *scriptedFrontend) Stopped(stop *debugger.Stop) debugger.Action {
+	frontend.stops = append(frontend.stops, stop)
+
+	if len(frontend.actions) == 0 {
+		return debugger.Continue
+	}
+
+	action := frontend.actions[0]
+	frontend.actions = frontend.actions[1:]
+	return action
+}
+
+func TestDebuggerStepping(t *testing.T) {
+	tests := []struct {
+		breakpoints []int
+		actions     []debugger.Action
+		lines       []int
+	}{
+		{[]int{}, []debugger.Action{debugger.StepOver, debugger.StepOver, debugger.StepOver}, []int{1, 6, 7, 8}},
+		{[]int{}, []debugger.Action{debugger.StepOver, debugger.StepIn, debugger.StepIn, debugger.StepIn}, []int{1, 6, 2, 3, 7}},
+		{[]int{}, []debugger.Action{debugger.StepOver, debugger.StepIn, debugger.StepOut}, []int{1, 6, 2, 7}},
+		{[]int{3}, []debugger.Action{debugger.Continue, debugger.Continue}, []int{1, 3, 3}},
+		{[]int{7}, []debugger.Action{debugger.Continue, debugger.StepOver}, []int{1, 7, 8}},
+	}
+
+	for _, test := range tests {
+		frontend := &scriptedFrontend{actions: test.actions}
+		dbg := debugger.NewDebugger(frontend, true)
+
+		for _, line := range test.breakpoints {
+			dbg.SetBreakpoint("", line)
+		}
+
+		result := dbg.Run(testParse(t, input), object.NewEnvironment())
+		assert.Equal(t, "6", result.Inspect())
+		lines := []int{}
+
+		for _, stop := range frontend.stops {
+			lines = append(lines, stop.Frames[0].Line)
+		}
+
+		assert.Equal(t, test.lines, lines)
+	}
+}
+
+func TestDebuggerCallStack(t *testing.T) {
+	frontend := &scriptedFrontend{}
+	dbg := debugger.NewDebugger(frontend, false)
+	dbg.SetBreakpoint("", 2)
+	dbg.Run(testParse(t, input), object.NewEnvironment())
+	assert.Len(t, frontend.stops, 2)
+
+	frames := frontend.stops[1].Frames
+	assert.Len(t, frames, 2)
+	assert.Equal(t, "add", frames[0].Function)
+	assert.Equal(t, 2, frames[0].Line)
+	assert.Equal(t, "<main>", frames[1].Function)
+	assert.Equal(t, 7, frames[1].Line)
+
+	a, _ := frames[0].Env.Get("a")
+	assert.Equal(t, "3", a.Inspect())
+	x, _ := frames[1].Env.Get("x")
+	assert.Equal(t, "3", x.Inspect())
+}
+
+func TestDebuggerQuit(t *testing.T) {
+	frontend := &scriptedFrontend{actions: []debugger.Action{debugger.Quit}}
+	dbg := debugger.NewDebugger(frontend, true)
+	result := dbg.Run(testParse(t, input), object.NewEnvironment())
+	errObj, ok := result.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "debugger terminated", errObj.Message)
+	assert.Len(t, frontend.stops, 1)
+}
+
+func TestCLI(t *testing.T) {
+	commands := strings.Join([]string{"b 2", "breakpoints", "c", "bt", "locals", "f 1", "p x", "scopes", "c", "q"}, "\n")
+	var out bytes.Buffer
+	cli := debugger.NewCLI(strings.NewReader(commands), &out, "", input)
+	cli.Run(testParse(t, input), object.NewEnvironment())
+
+	expected := []string{
+		"Breakpoint set at line 2",
+		"line 2",
+		"Stopped (breakpoint) in add at line 2",
+		"* #0 add at line 2, column 2",
+		"  #1 <main> at line 6, column 9",
+		"a = 1\nb = 2\n",
+		"#1 <main> at line 6",
+		"identifier not found: x",
+		"scope 0:\n\tadd = ",
+	}
+
+	for _, line := range expected {
+		assert.Contains(t, out.String(), line)
+	}
+}
+
+func TestDebuggerModules(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "main.monkey")
+	libPath := filepath.Join(dir, "lib.monkey")
+	source := "let lib = import \"lib\";\nlet x = lib.double(2);\nx;"
+	assert.NoError(t, os.WriteFile(mainPath, []byte(source), 0o644))
+	assert.NoError(t, os.WriteFile(libPath, []byte("export let double = fn(x) {\n\tlet y = x * 2;\n\ty;\n};"), 0o644))
+
+	tests := []struct {
+		file     string
+		files    []string
+		function string
+	}{
+		{mainPath, []string{mainPath}, "<main>"},
+		{libPath, []string{libPath, mainPath}, "<anonymous>"},
+	}
+
+	for _, test := range tests {
+		frontend := &scriptedFrontend{}
+		dbg := debugger.NewDebugger(frontend, false)
+		dbg.SetBreakpoint(test.file, 2)
+		env := object.NewEnvironment()
+		evaluator.SetFile(env, mainPath)
+		result := dbg.Run(testParse(t, source), env)
+		assert.Equal(t, "4", result.Inspect())
+		assert.Len(t, frontend.stops, 1)
+
+		frames := frontend.stops[0].Frames
+		assert.Equal(t, test.function, frames[0].Function)
+		assert.Equal(t, 2, frames[0].Line)
+		files := []string{}
+
+		for _, frame := range frames {
+			files = append(files, frame.File)
+		}
+
+		assert.Equal(t, test.files, files)
+	}
+
+	commands := strings.Join([]string{"b lib.monkey:2", "breakpoints", "c", "bt", "q"}, "\n")
+	var out bytes.Buffer
+	wd, _ := os.Getwd()
+	assert.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+
+	cli := debugger.NewCLI(strings.NewReader(commands), &out, mainPath, source)
+	env := object.NewEnvironment()
+	evaluator.SetFile(env, mainPath)
+	cli.Run(testParse(t, source), env)
+
+	expected := []string{
+		"Breakpoint set at lib.monkey:2",
+		"Stopped (breakpoint) in <anonymous> at lib.monkey:2\n   2\t\tlet y = x * 2;",
+		"* #0 <anonymous> at lib.monkey:2, column 2",
+		"  #1 <main> at line 2, column 12",
+	}
+
+	for _, line := range expected {
+		assert.Contains(t, out.String(), line)
+	}
+}
+
+func testParse(t *testing.T, input string) *ast.Program {
+	lex := lexer.NewLexer(input)
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+	assert.Empty(t, par.Errors())
+	resolver.Resolve(program)
+	return program
+}
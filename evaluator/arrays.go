@@ -0,0 +1,410 @@
+package evaluator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+func init() {
+	registerBuiltins(arrayBuiltins)
+}
+
+var arrayBuiltins = map[string]*object.Builtin{
+	"map": {
+		Signature: object.Signature{
+			Doc:        "Returns a new array with the function applied to each element.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj), param("fn", object.FunctionObj, object.BuiltinObj)},
+			Returns:    object.ArrayObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			elements := args[0].(*object.Array).Elements
+			result := make([]object.Object, len(elements))
+
+			for i, element := range elements {
+				mapped := applyCallback(args[1], element)
+
+				if isError(mapped) {
+					return mapped
+				}
+
+				result[i] = mapped
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"filter": {
+		Signature: object.Signature{
+			Doc:        "Returns a new array with the elements the function returns a truthy value for.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj), param("fn", object.FunctionObj, object.BuiltinObj)},
+			Returns:    object.ArrayObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			result := []object.Object{}
+
+			for _, element := range args[0].(*object.Array).Elements {
+				keep := applyCallback(args[1], element)
+
+				if isError(keep) {
+					return keep
+				}
+
+				if isTruthy(keep) {
+					result = append(result, element)
+				}
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"reduce": {
+		Signature: object.Signature{
+			Doc: "Combines the elements from left to right by calling the function with the accumulator and each element. " +
+				"Without an initial value the first element is used.",
+			Parameters: []object.Parameter{
+				param("arr", object.ArrayObj),
+				param("fn", object.FunctionObj, object.BuiltinObj),
+				optional("initial"),
+			},
+			Returns: object.AnyObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			elements := args[0].(*object.Array).Elements
+			var accumulator object.Object
+
+			if len(args) == 3 {
+				accumulator = args[2]
+			} else if len(elements) > 0 {
+				accumulator = elements[0]
+				elements = elements[1:]
+			} else {
+				return newError("reduce of empty array with no initial value")
+			}
+
+			for _, element := range elements {
+				accumulator = applyCallback(args[1], accumulator, element)
+
+				if isError(accumulator) {
+					return accumulator
+				}
+			}
+
+			return accumulator
+		},
+	},
+	"each": {
+		Signature: object.Signature{
+			Doc:        "Calls the function for each element.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj), param("fn", object.FunctionObj, object.BuiltinObj)},
+			Returns:    object.NullObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			for _, element := range args[0].(*object.Array).Elements {
+				if result := applyCallback(args[1], element); isError(result) {
+					return result
+				}
+			}
+
+			return NullObj
+		},
+	},
+	"find": {
+		Signature: object.Signature{
+			Doc:        "Returns the first element the function returns a truthy value for or null.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj), param("fn", object.FunctionObj, object.BuiltinObj)},
+			Returns:    object.AnyObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			for _, element := range args[0].(*object.Array).Elements {
+				found := applyCallback(args[1], element)
+
+				if isError(found) {
+					return found
+				}
+
+				if isTruthy(found) {
+					return element
+				}
+			}
+
+			return NullObj
+		},
+	},
+	"any": {
+		Signature: object.Signature{
+			Doc:        "Reports whether any element is truthy, either by itself or as returned by the function.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj), optional("fn", object.FunctionObj, object.BuiltinObj)},
+			Returns:    object.BooleanObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			return testElements(args, true)
+		},
+	},
+	"all": {
+		Signature: object.Signature{
+			Doc:        "Reports whether all elements are truthy, either by themselves or as returned by the function.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj), optional("fn", object.FunctionObj, object.BuiltinObj)},
+			Returns:    object.BooleanObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			return testElements(args, false)
+		},
+	},
+	"zip": {
+		Signature: object.Signature{
+			Doc:        "Groups the elements at the same index of all arrays, stopping at the end of the shortest one.",
+			Parameters: []object.Parameter{variadic("arrs", object.ArrayObj)},
+			Returns:    object.ArrayObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			if len(args) == 0 {
+				return &object.Array{Elements: []object.Object{}}
+			}
+
+			length := len(args[0].(*object.Array).Elements)
+
+			for _, arg := range args[1:] {
+				if argLength := len(arg.(*object.Array).Elements); argLength < length {
+					length = argLength
+				}
+			}
+
+			result := make([]object.Object, length)
+
+			for i := range result {
+				group := make([]object.Object, len(args))
+
+				for j, arg := range args {
+					group[j] = arg.(*object.Array).Elements[i]
+				}
+
+				result[i] = &object.Array{Elements: group}
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"enumerate": {
+		Signature: object.Signature{
+			Doc:        "Pairs each element with its index.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj)},
+			Returns:    object.ArrayObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			elements := args[0].(*object.Array).Elements
+			result := make([]object.Object, len(elements))
+
+			for i, element := range elements {
+				result[i] = &object.Array{Elements: []object.Object{newInteger(int64(i)), element}}
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"flatten": {
+		Signature: object.Signature{
+			Doc:        "Replaces nested arrays by their elements, up to the given depth which defaults to 1.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj), optional("depth", object.IntegerObj)},
+			Returns:    object.ArrayObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			depth := int64(1)
+
+			if len(args) == 2 {
+				depth = args[1].(*object.Integer).Value
+			}
+
+			return &object.Array{Elements: flatten(args[0].(*object.Array).Elements, depth)}
+		},
+	},
+	"range": {
+		Signature: object.Signature{
+			Doc: "Returns the integers from start (inclusive, defaults to 0) to end (exclusive) in steps of step " +
+				"(defaults to 1).",
+			Parameters: []object.Parameter{
+				param("start", object.IntegerObj),
+				optional("end", object.IntegerObj),
+				optional("step", object.IntegerObj),
+			},
+			Returns: object.ArrayObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			start, end, step := int64(0), args[0].(*object.Integer).Value, int64(1)
+
+			if len(args) > 1 {
+				start, end = end, args[1].(*object.Integer).Value
+			}
+
+			if len(args) > 2 {
+				step = args[2].(*object.Integer).Value
+			}
+
+			if step == 0 {
+				return newError("range step must not be zero")
+			}
+
+			result := []object.Object{}
+			count := steps(start, end, step)
+
+			for n := uint64(0); n < count; n++ {
+				result = append(result, newInteger(start+int64(n)*step))
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"reverse": {
+		Signature: object.Signature{
+			Doc:        "Returns the elements of an array or the characters of a string in reverse order.",
+			Parameters: []object.Parameter{param("value", object.ArrayObj, object.StringObj)},
+			Returns:    object.AnyObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			if str, ok := args[0].(*object.String); ok {
+				runes := []rune(str.Value)
+
+				for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+					runes[i], runes[j] = runes[j], runes[i]
+				}
+
+				return &object.String{Value: string(runes)}
+			}
+
+			elements := args[0].(*object.Array).Elements
+			result := make([]object.Object, len(elements))
+
+			for i, element := range elements {
+				result[len(elements)-1-i] = element
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"sort": {
+		Signature: object.Signature{
+			Doc: "Returns a sorted copy of an array of integers or strings. The optional comparator returns a " +
+				"negative integer or true if its first argument goes first.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj), optional("fn", object.FunctionObj, object.BuiltinObj)},
+			Returns:    object.ArrayObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			result := make([]object.Object, len(args[0].(*object.Array).Elements))
+			copy(result, args[0].(*object.Array).Elements)
+			var err object.Object
+
+			sort.SliceStable(result, func(i, j int) bool {
+				if err != nil {
+					return false
+				}
+
+				var less bool
+
+				if len(args) == 2 {
+					less, err = compareWithCallback(args[1], result[i], result[j])
+				} else {
+					less, err = compareObjects(result[i], result[j])
+				}
+
+				return less
+			})
+
+			if err != nil {
+				return err
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+	"uniq": {
+		Signature: object.Signature{
+			Doc:        "Returns the elements without duplicates, keeping the first occurrence.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj)},
+			Returns:    object.ArrayObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			seen := make(map[string]bool)
+			result := []object.Object{}
+
+			for _, element := range args[0].(*object.Array).Elements {
+				key := uniqueKey(element)
+
+				if !seen[key] {
+					seen[key] = true
+					result = append(result, element)
+				}
+			}
+
+			return &object.Array{Elements: result}
+		},
+	},
+}
+
+func testElements(args []object.Object, wanted bool) object.Object {
+	for _, element := range args[0].(*object.Array).Elements {
+		if len(args) == 2 {
+			element = applyCallback(args[1], element)
+
+			if isError(element) {
+				return element
+			}
+		}
+
+		if isTruthy(element) == wanted {
+			return nativeBoolToBooleanObject(wanted)
+		}
+	}
+
+	return nativeBoolToBooleanObject(!wanted)
+}
+
+func flatten(elements []object.Object, depth int64) []object.Object {
+	result := []object.Object{}
+
+	for _, element := range elements {
+		if arr, ok := element.(*object.Array); ok && depth > 0 {
+			result = append(result, flatten(arr.Elements, depth-1)...)
+		} else {
+			result = append(result, element)
+		}
+	}
+
+	return result
+}
+
+func compareObjects(left, right object.Object) (bool, object.Object) {
+	switch {
+	case left.Type() == object.IntegerObj && right.Type() == object.IntegerObj:
+		return left.(*object.Integer).Value < right.(*object.Integer).Value, nil
+	case left.Type() == object.StringObj && right.Type() == object.StringObj:
+		return left.(*object.String).Value < right.(*object.String).Value, nil
+	default:
+		return false, newError("cannot compare %s and %s", left.Type(), right.Type())
+	}
+}
+
+func compareWithCallback(fn, left, right object.Object) (bool, object.Object) {
+	result := applyCallback(fn, left, right)
+
+	switch result := result.(type) {
+	case *object.Error:
+		return false, result
+	case *object.Integer:
+		return result.Value < 0, nil
+	case *object.Boolean:
+		return result.Value, nil
+	default:
+		return false, newError("comparator must return INTEGER or BOOLEAN, got %s", result.Type())
+	}
+}
+
+// Scalars are compared by value, everything else by identity
+func uniqueKey(obj object.Object) string {
+	switch obj.(type) {
+	case *object.Integer, *object.String, *object.Boolean, *object.Null:
+		return string(obj.Type()) + ":" + obj.Inspect()
+	default:
+		return fmt.Sprintf("%p", obj)
+	}
+}
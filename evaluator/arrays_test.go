@@ -0,0 +1,107 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalArrayBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"map([1, 2, 3], fn(x) { x * 2 })", "[2, 4, 6]"},
+		{"map([], fn(x) { x * 2 })", "[]"},
+		{`map(["a", "bc"], len)`, "[1, 2]"},
+		{"map([1], 2)", "ERROR: invalid argument. got INTEGER, but expected FUNCTION or BUILTIN"},
+		{"map([1, true], fn(x) { -x })", "ERROR: unknown operator: -BOOLEAN"},
+
+		{"filter([1, 2, 3, 4], fn(x) { x > 2 })", "[3, 4]"},
+		{"filter([1, 2], fn(x) { false })", "[]"},
+
+		{"reduce([1, 2, 3], fn(acc, x) { acc + x })", 6},
+		{"reduce([1, 2, 3], fn(acc, x) { acc + x }, 10)", 16},
+		{`reduce(["a", "b"], fn(acc, x) { acc + x }, "")`, "ab"},
+		{"reduce([], fn(acc, x) { acc + x }, 0)", 0},
+		{"reduce([], fn(acc, x) { acc + x })", "ERROR: reduce of empty array with no initial value"},
+
+		{"each([1, 2], fn(x) { x })", nil},
+		{"each([1, true], fn(x) { x + 1 })", "ERROR: type mismatch: BOOLEAN + INTEGER"},
+
+		{"find([1, 2, 3], fn(x) { x > 1 })", 2},
+		{"find([1, 2, 3], fn(x) { x > 3 })", nil},
+
+		{"any([1, 2, 3], fn(x) { x > 2 })", true},
+		{"any([1, 2, 3], fn(x) { x > 3 })", false},
+		{"any([false, 1])", true},
+		{"any([])", false},
+		{"all([1, 2, 3], fn(x) { x > 0 })", true},
+		{"all([1, 2, 3], fn(x) { x > 1 })", false},
+		{"all([true, false])", false},
+		{"all([])", true},
+
+		{"zip([1, 2, 3], [4, 5])", "[[1, 4], [2, 5]]"},
+		{`zip([1], ["a"], [true])`, "[[1, a, true]]"},
+		{"zip()", "[]"},
+		{"zip([1], 2)", "ERROR: invalid argument. got INTEGER, but expected ARRAY"},
+
+		{`enumerate(["a", "b"])`, "[[0, a], [1, b]]"},
+
+		{"flatten([1, [2, [3, [4]]]])", "[1, 2, [3, [4]]]"},
+		{"flatten([1, [2, [3, [4]]]], 2)", "[1, 2, 3, [4]]"},
+		{"flatten([[]])", "[]"},
+
+		{"range(3)", "[0, 1, 2]"},
+		{"range(2, 5)", "[2, 3, 4]"},
+		{"range(0, 10, 3)", "[0, 3, 6, 9]"},
+		{"range(3, 0, -1)", "[3, 2, 1]"},
+		{"range(3, 0)", "[]"},
+		{"range(0, 9223372036854775807, 9223372036854775807)", "[0]"},
+		{"range(9223372036854775807, 0, -9223372036854775807)", "[9223372036854775807]"},
+		{"range(-9223372036854775807, 9223372036854775807, 9223372036854775807)", "[-9223372036854775807, 0]"},
+		{"range(0, 3, 0)", "ERROR: range step must not be zero"},
+
+		{"reverse([1, 2, 3])", "[3, 2, 1]"},
+		{`reverse("äbc")`, "cbä"},
+
+		{"sort([3, 1, 2])", "[1, 2, 3]"},
+		{`sort(["b", "c", "a"])`, "[a, b, c]"},
+		{"sort([3, 1, 2], fn(a, b) { b - a })", "[3, 2, 1]"},
+		{"sort([3, 1, 2], fn(a, b) { a > b })", "[3, 2, 1]"},
+		{`sort([[2, "b"], [1, "a"], [2, "a"]], fn(a, b) { a[0] < b[0] })`, "[[1, a], [2, b], [2, a]]"},
+		{`sort([1, "a"])`, "ERROR: cannot compare STRING and INTEGER"},
+		{`sort([1, 2], fn(a, b) { "x" })`, "ERROR: comparator must return INTEGER or BOOLEAN, got STRING"},
+
+		{`uniq([1, 2, 1, "1", "a", "a", true, true])`, "[1, 2, 1, a, true]"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			assert.Equal(t, expected, evaluated.Inspect(), test.input)
+		case nil:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestEvalCallbackStackTrace(t *testing.T) {
+	input := `let double = fn(x) {
+	x * 2
+};
+
+map([1, "two"], double);`
+
+	evaluated := testEval(t, input)
+	errObj, ok := evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "ERROR: type mismatch: STRING * INTEGER\n\tat map callback (2:2)\n\tat <main> (5:1)", errObj.Traceback())
+}
@@ -0,0 +1,138 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+func init() {
+	registerBuiltins(assertionBuiltins)
+}
+
+var assertionBuiltins = map[string]*object.Builtin{
+	"assert": {
+		Signature: object.Signature{
+			Doc:        "Fails with an AssertionError if the value is not truthy.",
+			Parameters: []object.Parameter{param("value"), optional("message", object.StringObj)},
+			Returns:    object.NullObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			if !isTruthy(args[0]) {
+				return newAssertionError(args[1:], "assert failed: got %s", args[0].Inspect())
+			}
+
+			return NullObj
+		},
+	},
+	"assert_eq": {
+		Signature: object.Signature{
+			Doc: "Fails with an AssertionError if the actual value is not equal to the expected one. " +
+				"Arrays and hashes are equal if their elements are.",
+			Parameters: []object.Parameter{param("actual"), param("expected"), optional("message", object.StringObj)},
+			Returns:    object.NullObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			if !deepEqual(args[0], args[1]) {
+				return newAssertionError(args[2:], "assert_eq failed\n%s", diff(args[0], args[1]))
+			}
+
+			return NullObj
+		},
+	},
+	"assert_error": {
+		Signature: object.Signature{
+			Doc: "Calls the function and fails with an AssertionError if it does not raise an error with the message. " +
+				"Without a message any error is accepted. Returns the error as an exception.",
+			Parameters: []object.Parameter{param("fn", object.FunctionObj, object.BuiltinObj), optional("message", object.StringObj)},
+			Returns:    object.ExceptionObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			result := applyCallback(args[0])
+			errObj, ok := result.(*object.Error)
+
+			if !ok {
+				return newAssertionError(nil, "assert_error failed: got %s instead of an error", result.Inspect())
+			}
+
+			if len(args) > 1 && errObj.Message != args[1].(*object.String).Value {
+				actual := &object.String{Value: errObj.Message}
+				return newAssertionError(nil, "assert_error failed: wrong message\n%s", diff(actual, args[1]))
+			}
+
+			return &object.Exception{Error: errObj}
+		},
+	},
+}
+
+// The message given to an assertion is put in front of the description of the
+// failure
+func newAssertionError(message []object.Object, format string, args ...any) *object.Error {
+	text := fmt.Sprintf(format, args...)
+
+	if len(message) > 0 {
+		text = message[0].(*object.String).Value + ": " + text
+	}
+
+	return &object.Error{Kind: "AssertionError", Message: text}
+}
+
+// Integers, strings, booleans and null are equal by value, arrays and hashes
+// if their elements are and all other objects by identity
+func deepEqual(left, right object.Object) bool {
+	switch left := left.(type) {
+	case *object.Array:
+		arr, ok := right.(*object.Array)
+
+		if !ok || len(arr.Elements) != len(left.Elements) {
+			return false
+		}
+
+		for i, element := range left.Elements {
+			if !deepEqual(element, arr.Elements[i]) {
+				return false
+			}
+		}
+
+		return true
+	case *object.Hash:
+		hash, ok := right.(*object.Hash)
+
+		if !ok || len(hash.Pairs) != len(left.Pairs) {
+			return false
+		}
+
+		for key, pair := range left.Pairs {
+			other, ok := hash.Pairs[key]
+
+			if !ok || !deepEqual(pair.Value, other.Value) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return objectsEqual(left, right)
+	}
+}
+
+// Shows both values and points to the first character they differ in. Values
+// which look the same are told apart by their type.
+func diff(actual, expected object.Object) string {
+	actualText, expectedText := actual.Inspect(), expected.Inspect()
+
+	if actualText == expectedText {
+		actualText += fmt.Sprintf(" (%s)", actual.Type())
+		expectedText += fmt.Sprintf(" (%s)", expected.Type())
+	}
+
+	actualRunes, expectedRunes := []rune(actualText), []rune(expectedText)
+	column := 0
+
+	for column < len(actualRunes) && column < len(expectedRunes) && actualRunes[column] == expectedRunes[column] {
+		column++
+	}
+
+	return fmt.Sprintf("\texpected: %s\n\tactual:   %s\n\t          %s^", expectedText, actualText, strings.Repeat(" ", column))
+}
@@ -0,0 +1,66 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalAssertions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"assert(true)", ""},
+		{"assert(1)", ""},
+		{"assert(false)", "assert failed: got false"},
+		{"assert(first([]))", "assert failed: got null"},
+		{`assert(1 > 2, "ordering")`, "ordering: assert failed: got false"},
+		{"assert_eq(1 + 1, 2)", ""},
+		{`assert_eq("a", "a")`, ""},
+		{"assert_eq([1, [2]], [1, [2]])", ""},
+		{`assert_eq({"a": 1, "b": 2}, {"b": 2, "a": 1})`, ""},
+		{"assert_eq(1, 2)", "assert_eq failed\n\texpected: 2\n\tactual:   1\n\t          ^"},
+		{"assert_eq([1, 2, 3], [1, 2, 4])", "assert_eq failed\n\texpected: [1, 2, 4]\n\tactual:   [1, 2, 3]\n\t                 ^"},
+		{`assert_eq("1", 1)`, "assert_eq failed\n\texpected: 1 (INTEGER)\n\tactual:   1 (STRING)\n\t             ^"},
+		{`assert_eq({"a": 1}, {"a": 1, "b": 2}, "hash")`, "hash: assert_eq failed\n\texpected: {a: 1, b: 2}\n\tactual:   {a: 1}\n\t               ^"},
+		{`assert_error(fn() { 1 + true })`, ""},
+		{`assert_error(fn() { throw "boom" }, "boom")`, ""},
+		{`assert_error(fn() { throw "boom" }, "bang")`, "assert_error failed: wrong message\n\texpected: bang\n\tactual:   boom\n\t           ^"},
+		{"assert_error(fn() { 1 })", "assert_error failed: got 1 instead of an error"},
+		{`assert_error(fn() { 1 + true }).type`, "RuntimeError"},
+		{"assert_eq(1)", "wrong number of arguments. got 1, but expected 2 to 3"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+
+		switch evaluated := evaluated.(type) {
+		case *object.Error:
+			assert.Equal(t, test.expected, evaluated.Message, test.input)
+		case *object.String:
+			assert.Equal(t, test.expected, evaluated.Value, test.input)
+		case *object.Exception:
+			assert.Equal(t, "", test.expected, test.input)
+		default:
+			assert.Equal(t, "", test.expected, test.input)
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestEvalAssertionErrorKind(t *testing.T) {
+	evaluated := testEval(t, `try { assert(false) } catch (e) { e.type }`)
+	testStringObject(t, evaluated, "AssertionError")
+}
+
+func TestApply(t *testing.T) {
+	fn := testEval(t, "fn(x) { x * 2 }")
+	testIntegerObject(t, evaluator.Apply("double", fn, &object.Integer{Value: 2}), 4)
+
+	errObj, ok := evaluator.Apply("missing", nil).(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "not a function: NULL", errObj.Message)
+}
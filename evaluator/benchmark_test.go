@@ -0,0 +1,44 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/benchmarks"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/optimizer"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+)
+
+// Programs are parsed once, so only their evaluation is measured
+func BenchmarkEval(b *testing.B) {
+	for _, program := range benchmarks.Programs() {
+		b.Run(program.Name, func(b *testing.B) {
+			benchmarkEval(b, program.Source, false)
+		})
+	}
+}
+
+func BenchmarkEvalOptimized(b *testing.B) {
+	for _, program := range benchmarks.Programs() {
+		b.Run(program.Name, func(b *testing.B) {
+			benchmarkEval(b, program.Source, true)
+		})
+	}
+}
+
+func benchmarkEval(b *testing.B, input string, optimize bool) {
+	program := parse(input)
+
+	if optimize {
+		optimizer.Optimize(program)
+	}
+
+	resolver.Resolve(program)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		evaluator.Eval(program, object.NewEnvironment())
+	}
+}
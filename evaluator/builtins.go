@@ -1,34 +1,36 @@
 package evaluator
 
-import "github.com/henningstorck/monkey-interpreter/object"
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/henningstorck/monkey-interpreter/object"
+)
 
 var builtins = map[string]*object.Builtin{
 	"len": {
+		Signature: object.Signature{
+			Doc:        "Returns the number of characters of a string or the number of elements of an array.",
+			Parameters: []object.Parameter{param("value", object.StringObj, object.ArrayObj)},
+			Returns:    object.IntegerObj,
+		},
 		Function: func(args ...object.Object) object.Object {
-			if err := expectArguments(args, 1); err != nil {
-				return err
-			}
-
 			switch arg := args[0].(type) {
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
-			case *object.Array:
-				return &object.Integer{Value: int64(len(arg.Elements))}
+				return newInteger(int64(utf8.RuneCountInString(arg.Value)))
 			default:
-				return newError("invalid argument. got %s", args[0].Type())
+				return newInteger(int64(len(arg.(*object.Array).Elements)))
 			}
 		},
 	},
 	"first": {
+		Signature: object.Signature{
+			Doc:        "Returns the first element of an array or null if it is empty.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj)},
+			Returns:    object.AnyObj,
+		},
 		Function: func(args ...object.Object) object.Object {
-			if err := expectArguments(args, 1); err != nil {
-				return err
-			}
-
-			if err := expectArgumentType(args[0], object.ArrayObj); err != nil {
-				return err
-			}
-
 			arr := args[0].(*object.Array)
 
 			if (len(arr.Elements)) > 0 {
@@ -39,15 +41,12 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"last": {
+		Signature: object.Signature{
+			Doc:        "Returns the last element of an array or null if it is empty.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj)},
+			Returns:    object.AnyObj,
+		},
 		Function: func(args ...object.Object) object.Object {
-			if err := expectArguments(args, 1); err != nil {
-				return err
-			}
-
-			if err := expectArgumentType(args[0], object.ArrayObj); err != nil {
-				return err
-			}
-
 			arr := args[0].(*object.Array)
 			length := len(arr.Elements)
 
@@ -59,15 +58,12 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"rest": {
+		Signature: object.Signature{
+			Doc:        "Returns a new array containing all elements but the first one or null if it is empty.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj)},
+			Returns:    object.AnyObj,
+		},
 		Function: func(args ...object.Object) object.Object {
-			if err := expectArguments(args, 1); err != nil {
-				return err
-			}
-
-			if err := expectArgumentType(args[0], object.ArrayObj); err != nil {
-				return err
-			}
-
 			arr := args[0].(*object.Array)
 			length := len(arr.Elements)
 
@@ -81,15 +77,12 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"push": {
+		Signature: object.Signature{
+			Doc:        "Returns a new array with the value appended.",
+			Parameters: []object.Parameter{param("arr", object.ArrayObj), param("value")},
+			Returns:    object.ArrayObj,
+		},
 		Function: func(args ...object.Object) object.Object {
-			if err := expectArguments(args, 2); err != nil {
-				return err
-			}
-
-			if err := expectArgumentType(args[0], object.ArrayObj); err != nil {
-				return err
-			}
-
 			arr := args[0].(*object.Array)
 			length := len(arr.Elements)
 			newElements := make([]object.Object, length+1)
@@ -100,17 +93,102 @@ var builtins = map[string]*object.Builtin{
 	},
 }
 
-func expectArguments(args []object.Object, expected int) *object.Error {
-	if len(args) != expected {
-		return newError("wrong number of arguments. got %d, but expected %d", len(args), expected)
+var helpBuiltin = &object.Builtin{
+	Signature: object.Signature{
+		Doc:        "Describes a function or lists all builtin functions.",
+		Parameters: []object.Parameter{optional("fn", object.BuiltinObj, object.FunctionObj)},
+		Returns:    object.StringObj,
+	},
+	Function: func(args ...object.Object) object.Object {
+		if len(args) == 0 {
+			lines := []string{}
+
+			for _, builtin := range Builtins() {
+				lines = append(lines, builtin.Signature.String())
+			}
+
+			return &object.String{Value: strings.Join(lines, "\n")}
+		}
+
+		switch fn := args[0].(type) {
+		case *object.Builtin:
+			return &object.String{Value: fn.Signature.String() + "\n" + fn.Signature.Doc}
+		default:
+			return &object.String{Value: fn.Inspect()}
+		}
+	},
+}
+
+func init() {
+	builtins["help"] = helpBuiltin
+	registerBuiltins(builtins)
+}
+
+func registerBuiltins(definitions map[string]*object.Builtin) {
+	for name, builtin := range definitions {
+		builtin.Signature.Name = name
+		builtins[name] = builtin
+	}
+}
+
+// Builtins returns all builtin functions sorted by name, so their signatures
+// can be inspected.
+func Builtins() []*object.Builtin {
+	names := make([]string, 0, len(builtins))
+
+	for name := range builtins {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	result := make([]*object.Builtin, len(names))
+
+	for i, name := range names {
+		result[i] = builtins[name]
+	}
+
+	return result
+}
+
+func param(name string, types ...object.ObjectType) object.Parameter {
+	return object.Parameter{Name: name, Types: types}
+}
+
+func optional(name string, types ...object.ObjectType) object.Parameter {
+	return object.Parameter{Name: name, Types: types, Optional: true}
+}
+
+func variadic(name string, types ...object.ObjectType) object.Parameter {
+	return object.Parameter{Name: name, Types: types, Variadic: true}
+}
+
+func checkArity(count, min, max int) *object.Error {
+	if msg := object.ArityError(count, min, max); msg != "" {
+		return newError("%s", msg)
 	}
 
 	return nil
 }
 
-func expectArgumentType(arg object.Object, objType object.ObjectType) *object.Error {
-	if arg.Type() != object.ArrayObj {
-		return newError("invalid argument. got %s, but expected %s", arg.Type(), objType)
+func checkSignature(sig *object.Signature, args []object.Object) *object.Error {
+	min, max := sig.Arity()
+
+	if err := checkArity(len(args), min, max); err != nil {
+		return err
+	}
+
+	for i, arg := range args {
+		param, _ := sig.Parameter(i)
+
+		if !param.Accepts(arg.Type()) {
+			types := make([]string, len(param.Types))
+
+			for j, paramType := range param.Types {
+				types[j] = string(paramType)
+			}
+
+			return newError("invalid argument. got %s, but expected %s", arg.Type(), strings.Join(types, " or "))
+		}
 	}
 
 	return nil
@@ -1,8 +1,10 @@
 package evaluator_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/henningstorck/monkey-interpreter/evaluator"
 	"github.com/henningstorck/monkey-interpreter/object"
 	"github.com/stretchr/testify/assert"
 )
@@ -15,7 +17,7 @@ func TestEvalBuiltinFunctions(t *testing.T) {
 		{`len("")`, 0},
 		{`len("four")`, 4},
 		{`len("hello world")`, 11},
-		{`len(1)`, "invalid argument. got INTEGER"},
+		{`len(1)`, "invalid argument. got INTEGER, but expected STRING or ARRAY"},
 		{`len("one", "two")`, "wrong number of arguments. got 2, but expected 1"},
 
 		{"len([])", 0},
@@ -46,7 +48,7 @@ func TestEvalBuiltinFunctions(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		evaluated := testEval(test.input)
+		evaluated := testEval(t, test.input)
 
 		switch expected := test.expected.(type) {
 		case int:
@@ -69,3 +71,36 @@ func TestEvalBuiltinFunctions(t *testing.T) {
 		}
 	}
 }
+
+func TestBuiltinSignatures(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"len", "builtin len(value: STRING | ARRAY): INTEGER"},
+		{"push", "builtin push(arr: ARRAY, value: ANY): ARRAY"},
+		{"substr", "builtin substr(str: STRING, start: INTEGER, length?: INTEGER): STRING"},
+		{"help(first)", "first(arr: ARRAY): ANY\nReturns the first element of an array or null if it is empty."},
+		{"help(fn(x) { x })", "fn(x) {\nx\n}"},
+		{"help(1)", "ERROR: invalid argument. got INTEGER, but expected BUILTIN or FUNCTION"},
+		{"help(len, len)", "ERROR: wrong number of arguments. got 2, but expected 0 to 1"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect())
+	}
+
+	evaluated := testEval(t, "help()")
+	lines := strings.Split(evaluated.Inspect(), "\n")
+	assert.Len(t, lines, len(evaluator.Builtins()))
+	assert.Contains(t, lines, "len(value: STRING | ARRAY): INTEGER")
+}
+
+func TestBuiltinsAreDocumented(t *testing.T) {
+	for _, builtin := range evaluator.Builtins() {
+		assert.NotEmpty(t, builtin.Signature.Name)
+		assert.NotEmpty(t, builtin.Signature.Doc, builtin.Signature.Name)
+		assert.NotEmpty(t, builtin.Signature.Returns, builtin.Signature.Name)
+	}
+}
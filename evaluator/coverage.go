@@ -0,0 +1,140 @@
+package evaluator
+
+import (
+	"sort"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/coverage"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+// Counts are kept per node, the programs they belong to are only walked to
+// find the statements which were never evaluated when coverage is stopped
+type coverageRecorder struct {
+	programs   map[*ast.Program]string
+	statements map[ast.Node]int64
+	branches   map[*ast.IfExpression]*[2]int64
+}
+
+var activeCoverage *coverageRecorder
+
+// StartCoverage records which statements and branches of programs loaded
+// from files are evaluated until StopCoverage is called.
+func StartCoverage() {
+	activeCoverage = &coverageRecorder{
+		programs:   make(map[*ast.Program]string),
+		statements: make(map[ast.Node]int64),
+		branches:   make(map[*ast.IfExpression]*[2]int64),
+	}
+}
+
+func StopCoverage() *coverage.Profile {
+	cover := activeCoverage
+	activeCoverage = nil
+	result := &coverage.Profile{}
+
+	if cover == nil {
+		return result
+	}
+
+	// A file may have been parsed more than once, so its statements are
+	// merged by position
+	files := map[string]*coverageFile{}
+
+	for program, path := range cover.programs {
+		if files[path] == nil {
+			files[path] = &coverageFile{statements: map[token.Position]int64{}, branches: map[token.Position]*[2]int64{}}
+		}
+
+		cover.collect(program, files[path])
+	}
+
+	for path, file := range files {
+		result.Files = append(result.Files, file.build(path))
+	}
+
+	sort.Slice(result.Files, func(i, j int) bool {
+		return result.Files[i].Path < result.Files[j].Path
+	})
+
+	return result
+}
+
+type coverageFile struct {
+	statements map[token.Position]int64
+	branches   map[token.Position]*[2]int64
+}
+
+func (cover *coverageRecorder) collect(program *ast.Program, file *coverageFile) {
+	addStatements := func(stmts []ast.Statement) {
+		for _, stmt := range stmts {
+			file.statements[stmt.Pos()] += cover.statements[stmt]
+		}
+	}
+
+	addStatements(program.Statements)
+
+	ast.Walk(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.BlockStatement:
+			addStatements(node.Statements)
+		case *ast.IfExpression:
+			if file.branches[node.Pos()] == nil {
+				file.branches[node.Pos()] = &[2]int64{}
+			}
+
+			if taken := cover.branches[node]; taken != nil {
+				file.branches[node.Pos()][0] += taken[0]
+				file.branches[node.Pos()][1] += taken[1]
+			}
+		}
+
+		return true
+	})
+}
+
+func (file *coverageFile) build(path string) *coverage.File {
+	result := &coverage.File{Path: path}
+
+	for pos, count := range file.statements {
+		result.Statements = append(result.Statements, coverage.Statement{Position: pos, Count: count})
+	}
+
+	for pos, taken := range file.branches {
+		result.Branches = append(result.Branches, coverage.Branch{Position: pos, Consequence: taken[0], Alternative: taken[1]})
+	}
+
+	sort.Slice(result.Statements, func(i, j int) bool {
+		return positionBefore(result.Statements[i].Position, result.Statements[j].Position)
+	})
+
+	sort.Slice(result.Branches, func(i, j int) bool {
+		return positionBefore(result.Branches[i].Position, result.Branches[j].Position)
+	})
+
+	return result
+}
+
+func positionBefore(left, right token.Position) bool {
+	return left.Line < right.Line || (left.Line == right.Line && left.Column < right.Column)
+}
+
+// Programs which were not loaded from a file, like the input of the REPL, are
+// not covered
+func coverProgram(program *ast.Program, env *object.Environment) {
+	if path := FileOf(env); path != "" {
+		activeCoverage.programs[program] = path
+	}
+}
+
+func coverBranch(ifExp *ast.IfExpression, branch int) {
+	taken := activeCoverage.branches[ifExp]
+
+	if taken == nil {
+		taken = &[2]int64{}
+		activeCoverage.branches[ifExp] = taken
+	}
+
+	taken[branch]++
+}
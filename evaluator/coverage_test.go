@@ -0,0 +1,58 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/coverage"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+	"github.com/henningstorck/monkey-interpreter/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoverage(t *testing.T) {
+	input := `let sign = fn(n) {
+	if (n < 0) { -1 } else { 1 }
+};
+let unused = fn() {
+	1
+};
+if (sign(5) > 0) { sign(6) };
+sign(7)`
+
+	program := parse(input)
+	resolver.Resolve(program)
+	env := object.NewEnvironment()
+	evaluator.SetFile(env, "/tmp/sign.monkey")
+	evaluator.StartCoverage()
+	evaluator.Eval(program, env)
+	prof := evaluator.StopCoverage()
+
+	assert.Len(t, prof.Files, 1)
+	file := prof.Files[0]
+	assert.Equal(t, "/tmp/sign.monkey", file.Path)
+
+	assert.Equal(t, []coverage.Statement{
+		{Position: token.Position{Line: 1, Column: 1}, Count: 1},
+		{Position: token.Position{Line: 2, Column: 2}, Count: 3},
+		{Position: token.Position{Line: 2, Column: 15}, Count: 0},
+		{Position: token.Position{Line: 2, Column: 27}, Count: 3},
+		{Position: token.Position{Line: 4, Column: 1}, Count: 1},
+		{Position: token.Position{Line: 5, Column: 2}, Count: 0},
+		{Position: token.Position{Line: 7, Column: 1}, Count: 1},
+		{Position: token.Position{Line: 7, Column: 20}, Count: 1},
+		{Position: token.Position{Line: 8, Column: 1}, Count: 1},
+	}, file.Statements)
+
+	assert.Equal(t, []coverage.Branch{
+		{Position: token.Position{Line: 2, Column: 2}, Consequence: 0, Alternative: 3},
+		{Position: token.Position{Line: 7, Column: 1}, Consequence: 1, Alternative: 0},
+	}, file.Branches)
+}
+
+func TestCoverageWithoutFile(t *testing.T) {
+	evaluator.StartCoverage()
+	evalProgram(parse("if (true) { 1 }"))
+	assert.Empty(t, evaluator.StopCoverage().Files)
+}
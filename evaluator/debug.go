@@ -0,0 +1,78 @@
+package evaluator
+
+import (
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+// Hook is called before each statement is evaluated. Returning an error aborts
+// the evaluation with that error.
+type Hook func(node ast.Node, env *object.Environment) *object.Error
+
+// Frame describes a Monkey function call which is currently being evaluated.
+type Frame struct {
+	Function string
+	Position token.Position
+	Env      *object.Environment
+}
+
+// callSite describes where a function is called from.
+type callSite struct {
+	name     string
+	position token.Position
+}
+
+var (
+	hook        Hook
+	callStack   []Frame
+	builtinSite callSite
+)
+
+func SetHook(h Hook) {
+	hook = h
+}
+
+// CallStack returns the frames of all active function calls, starting with the
+// outermost call.
+func CallStack() []Frame {
+	frames := make([]Frame, len(callStack))
+	copy(frames, callStack)
+	return frames
+}
+
+func newCallSite(call *ast.CallExpression) callSite {
+	site := callSite{name: "<anonymous>", position: call.Function.Pos()}
+
+	if ident, ok := call.Function.(*ast.Identifier); ok {
+		site.name = ident.Value
+	}
+
+	return site
+}
+
+func runHook(node ast.Node, env *object.Environment) *object.Error {
+	if activeProfiler != nil {
+		profileStatement(node, env)
+	}
+
+	if activeCoverage != nil {
+		activeCoverage.statements[node]++
+	}
+
+	if hook == nil {
+		return nil
+	}
+
+	return hook(node, env)
+}
+
+func pushFrame(site callSite, env *object.Environment) {
+	callStack = append(callStack, Frame{Function: site.name, Position: site.position, Env: env})
+}
+
+func popFrame() Frame {
+	frame := callStack[len(callStack)-1]
+	callStack = callStack[:len(callStack)-1]
+	return frame
+}
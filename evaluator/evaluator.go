@@ -5,6 +5,7 @@ import (
 
 	"github.com/henningstorck/monkey-interpreter/ast"
 	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/token"
 )
 
 var (
@@ -14,6 +15,14 @@ var (
 )
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	if activeTracer != nil {
+		return traceNode(node, env)
+	}
+
+	return eval(node, env)
+}
+
+func eval(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
@@ -34,9 +43,14 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return left
 		}
 
+		// Only evaluates the right side if the left one is null
+		if node.Operator == "??" && left != NullObj {
+			return left
+		}
+
 		right := Eval(node.Right, env)
 
-		if isError(right) {
+		if isError(right) || node.Operator == "??" {
 			return right
 		}
 
@@ -45,6 +59,18 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalBlockStatement(node, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
+	case *ast.ConditionalExpression:
+		condition := Eval(node.Condition, env)
+
+		if isError(condition) {
+			return condition
+		}
+
+		if isTruthy(condition) {
+			return Eval(node.Consequence, env)
+		}
+
+		return Eval(node.Alternative, env)
 	case *ast.ReturnStatement:
 		value := Eval(node.ReturnValue, env)
 
@@ -53,6 +79,44 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 
 		return &object.ReturnValue{Value: value}
+	case *ast.ThrowStatement:
+		value := Eval(node.Value, env)
+
+		if isError(value) {
+			return value
+		}
+
+		return newThrownError(value)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+	case *ast.SwitchExpression:
+		return evalSwitchExpression(node, env)
+	case *ast.ExportStatement:
+		return Eval(node.Statement, env)
+	case *ast.ImportExpression:
+		path := Eval(node.Path, env)
+
+		if isError(path) {
+			return path
+		}
+
+		return evalImportExpression(path, env)
+	case *ast.SliceExpression:
+		return evalSliceExpression(node, env)
+	case *ast.MemberExpression:
+		left := Eval(node.Left, env)
+
+		if isError(left) {
+			return left
+		}
+
+		if node.Optional && left == NullObj {
+			return NullObj
+		}
+
+		return evalMemberExpression(left, node.Property.Value)
 	case *ast.LetStatement:
 		value := Eval(node.Value, env)
 
@@ -60,19 +124,41 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return value
 		}
 
-		env.Set(node.Name.Value, value)
+		if node.Pattern != nil {
+			if err := bindPattern(node.Pattern, value, env); err != nil {
+				return err
+			}
+		} else {
+			bind(node.Name, value, env)
+		}
+
+		if activeTracer != nil {
+			traceLet(node, env)
+		}
+
 		return nil
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-
-		return &object.Function{
+		fn := &object.Function{
 			Parameters: params,
+			Defaults:   node.Defaults,
+			Rest:       node.Rest,
 			Env:        env,
 			Body:       body,
+			Locals:     node.Locals,
+		}
+
+		if node.Name != nil {
+			fn.Name = node.Name.Value
 		}
+
+		return fn
+	case *ast.FunctionDeclaration:
+		// Declarations are bound when entering their block
+		return nil
 	case *ast.CallExpression:
 		fn := Eval(node.Function, env)
 
@@ -86,7 +172,17 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return args[0]
 		}
 
-		return applyFunction(fn, args)
+		if len(node.Keywords) > 0 {
+			var err object.Object
+
+			if args, err = evalKeywordArguments(fn, args, node.Keywords, env); err != nil {
+				return err
+			}
+		}
+
+		return applyFunction(fn, args, newCallSite(node))
+	case *ast.SpreadExpression:
+		return newError("spread is only allowed in calls and array literals")
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
 
@@ -95,6 +191,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 
 		return &object.Array{Elements: elements}
+	case *ast.HashLiteral:
+		return evalHashLiteral(node, env)
 	case *ast.IndexExpression:
 		left := Eval(node.Left, env)
 
@@ -102,6 +200,10 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return left
 		}
 
+		if node.Optional && left == NullObj {
+			return NullObj
+		}
+
 		index := Eval(node.Index, env)
 
 		if isError(index) {
@@ -110,11 +212,11 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 
 		return evalIndexExpression(left, index)
 	case *ast.IntegerLiteral:
-		return &object.Integer{Value: node.Value}
+		return evalIntegerLiteral(node)
 	case *ast.BooleanLiteral:
 		return nativeBoolToBooleanObject(node.Value)
 	case *ast.StringLiteral:
-		return &object.String{Value: node.Value}
+		return evalStringLiteral(node)
 	default:
 		return nil
 	}
@@ -123,13 +225,24 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	var result object.Object
 
+	if activeCoverage != nil {
+		coverProgram(program, env)
+	}
+
+	hoistFunctions(program.Statements, env)
+
 	for _, stmt := range program.Statements {
+		if err := runHook(stmt, env); err != nil {
+			return err
+		}
+
 		result = Eval(stmt, env)
 
 		switch result := result.(type) {
 		case *object.ReturnValue:
 			return result.Value
 		case *object.Error:
+			setErrorPosition(result, stmt)
 			return result
 		}
 	}
@@ -139,14 +252,24 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 
 func evalBlockStatement(blockStmt *ast.BlockStatement, env *object.Environment) object.Object {
 	var result object.Object
+	hoistFunctions(blockStmt.Statements, env)
 
 	for _, stmt := range blockStmt.Statements {
+		if err := runHook(stmt, env); err != nil {
+			return err
+		}
+
 		result = Eval(stmt, env)
 
 		if result != nil {
 			resultType := result.Type()
 
-			if resultType == object.ReturnValueObj || resultType == object.ErrorObj {
+			if resultType == object.ReturnValueObj {
+				return result
+			}
+
+			if resultType == object.ErrorObj {
+				setErrorPosition(result.(*object.Error), stmt)
 				return result
 			}
 		}
@@ -155,6 +278,16 @@ func evalBlockStatement(blockStmt *ast.BlockStatement, env *object.Environment)
 	return result
 }
 
+// Binds all function declarations of a block up front, so they are able to
+// call each other regardless of their order
+func hoistFunctions(stmts []ast.Statement, env *object.Environment) {
+	for _, stmt := range stmts {
+		if fnDecl, ok := stmt.(*ast.FunctionDeclaration); ok {
+			bind(fnDecl.Function.Name, Eval(fnDecl.Function, env), env)
+		}
+	}
+}
+
 func evalPrefixExpression(operator string, right object.Object) object.Object {
 	switch operator {
 	case "!":
@@ -185,7 +318,7 @@ func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
 	}
 
 	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
+	return newInteger(-value)
 }
 
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
@@ -211,13 +344,17 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 
 	switch operator {
 	case "+":
-		return &object.Integer{Value: leftValue + rightValue}
+		return newInteger(leftValue + rightValue)
 	case "-":
-		return &object.Integer{Value: leftValue - rightValue}
+		return newInteger(leftValue - rightValue)
 	case "*":
-		return &object.Integer{Value: leftValue * rightValue}
+		return newInteger(leftValue * rightValue)
 	case "/":
-		return &object.Integer{Value: leftValue / rightValue}
+		if rightValue == 0 {
+			return newError("division by zero")
+		}
+
+		return newInteger(leftValue / rightValue)
 	case "<":
 		return nativeBoolToBooleanObject(leftValue < rightValue)
 	case ">":
@@ -248,7 +385,17 @@ func evalIfExpression(ifExp *ast.IfExpression, env *object.Environment) object.O
 		return condition
 	}
 
-	if isTruthy(condition) {
+	truthy := isTruthy(condition)
+
+	if activeCoverage != nil {
+		if truthy {
+			coverBranch(ifExp, 0)
+		} else {
+			coverBranch(ifExp, 1)
+		}
+	}
+
+	if truthy {
 		return Eval(ifExp.Consequence, env)
 	} else if ifExp.Alternative != nil {
 		return Eval(ifExp.Alternative, env)
@@ -257,15 +404,79 @@ func evalIfExpression(ifExp *ast.IfExpression, env *object.Environment) object.O
 	}
 }
 
+func evalSwitchExpression(switchExp *ast.SwitchExpression, env *object.Environment) object.Object {
+	subject := Eval(switchExp.Subject, env)
+
+	if isError(subject) {
+		return subject
+	}
+
+	body := switchExp.Default
+
+	for _, switchCase := range switchExp.Cases {
+		if matched, err := evalSwitchCase(switchCase, subject, env); err != nil {
+			return err
+		} else if matched {
+			body = switchCase.Body
+			break
+		}
+	}
+
+	if body == nil {
+		return NullObj
+	}
+
+	if result := Eval(body, env); result != nil {
+		return result
+	}
+
+	return NullObj
+}
+
+// Cases compare their values one after another until one is equal
+func evalSwitchCase(switchCase *ast.SwitchCase, subject object.Object, env *object.Environment) (bool, object.Object) {
+	for _, valueExp := range switchCase.Values {
+		value := Eval(valueExp, env)
+
+		if isError(value) {
+			return false, value
+		}
+
+		if objectsEqual(subject, value) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	case left.Type() == object.ArrayObj && index.Type() == object.IntegerObj:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.StringObj && index.Type() == object.IntegerObj:
+		return evalStringIndexExpression(left, index)
+	case left.Type() == object.HashObj:
+		return evalHashIndexExpression(left, index)
+	case left.Type() == object.ExceptionObj && index.Type() == object.StringObj:
+		return evalExceptionIndexExpression(left, index)
+	case left.Type() == object.ModuleObj && index.Type() == object.StringObj:
+		return evalModuleIndexExpression(left, index)
 	default:
 		return newError("index operator is not supported: %s", left.Type())
 	}
 }
 
+// Member access is a shorthand for indexing with the property name
+func evalMemberExpression(left object.Object, property string) object.Object {
+	switch left.Type() {
+	case object.HashObj, object.ModuleObj, object.ExceptionObj:
+		return evalIndexExpression(left, &object.String{Value: property})
+	default:
+		return newError("member access is not supported: %s", left.Type())
+	}
+}
+
 func evalArrayIndexExpression(arr, index object.Object) object.Object {
 	arrayObj := arr.(*object.Array)
 	indexValue := index.(*object.Integer).Value
@@ -278,6 +489,20 @@ func evalArrayIndexExpression(arr, index object.Object) object.Object {
 	return arrayObj.Elements[indexValue]
 }
 
+// Integers and strings are equal by value, all other objects by identity
+func objectsEqual(left, right object.Object) bool {
+	switch left := left.(type) {
+	case *object.Integer:
+		integer, ok := right.(*object.Integer)
+		return ok && integer.Value == left.Value
+	case *object.String:
+		str, ok := right.(*object.String)
+		return ok && str.Value == left.Value
+	default:
+		return left == right
+	}
+}
+
 func isTruthy(obj object.Object) bool {
 	switch obj {
 	case NullObj:
@@ -291,7 +516,15 @@ func isTruthy(obj object.Object) bool {
 	}
 }
 
+// Falls back to looking up resolved identifiers by name while their slot is
+// empty, as an outer binding of the same name might still be visible
 func evalIdentifier(ident *ast.Identifier, env *object.Environment) object.Object {
+	if ident.Resolved {
+		if value, ok := env.GetSlot(ident.Depth, ident.Slot); ok {
+			return value
+		}
+	}
+
 	if value, ok := env.Get(ident.Value); ok {
 		return value
 	}
@@ -307,42 +540,215 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	var result []object.Object
 
 	for _, exp := range exps {
+		spreadExp, spread := exp.(*ast.SpreadExpression)
+
+		if spread {
+			exp = spreadExp.Value
+		}
+
 		evaluated := Eval(exp, env)
 
 		if isError(evaluated) {
 			return []object.Object{evaluated}
 		}
 
-		result = append(result, evaluated)
+		if !spread {
+			result = append(result, evaluated)
+		} else if arr, ok := evaluated.(*object.Array); ok {
+			result = append(result, arr.Elements...)
+		} else {
+			return []object.Object{newError("cannot spread %s, expected %s", evaluated.Type(), object.ArrayObj)}
+		}
 	}
 
 	return result
 }
 
-func applyFunction(obj object.Object, args []object.Object) object.Object {
+func applyFunction(obj object.Object, args []object.Object, site callSite) object.Object {
 	switch fn := obj.(type) {
 	case *object.Function:
-		extEnv := extendFunctionEnv(fn, args)
+		extEnv, err := extendFunctionEnv(fn, args)
+
+		if err != nil {
+			return err
+		}
+
+		if fn.Name != "" {
+			site.name = fn.Name
+		}
+
+		pushFrame(site, extEnv)
+
+		if activeProfiler != nil {
+			profileEnter(site.name, FileOf(fn.Env), functionLine(fn))
+		}
+
+		if activeTracer != nil {
+			traceCall(site.name, fn, extEnv)
+		}
+
 		evaluated := Eval(fn.Body, extEnv)
+
+		if activeTracer != nil {
+			traceReturn(site.name, unwrapReturnValue(evaluated))
+		}
+
+		if activeProfiler != nil {
+			profileExit()
+		}
+
+		frame := popFrame()
+
+		if errObj, ok := evaluated.(*object.Error); ok {
+			errObj.Stack = append(errObj.Stack, object.StackFrame{
+				Function: frame.Function,
+				Position: frame.Position,
+			})
+		}
+
 		return unwrapReturnValue(evaluated)
 
 	case *object.Builtin:
-		return fn.Function(args...)
+		if err := checkSignature(&fn.Signature, args); err != nil {
+			return err
+		}
+
+		outerSite := builtinSite
+		builtinSite = callSite{name: fn.Signature.Name + " callback", position: site.position}
+		result := fn.Function(args...)
+		builtinSite = outerSite
+		return result
 
+	case nil:
+		return newError("not a function: %s", object.NullObj)
 	default:
 		return newError("not a function: %s", fn.Type())
 	}
 }
 
+// Apply calls a function from outside of a program, e.g. a test function. The
+// name is shown in the stack of errors if the function has none.
+func Apply(name string, fn object.Object, args ...object.Object) object.Object {
+	if result := applyFunction(fn, args, callSite{name: name}); result != nil {
+		return result
+	}
+
+	return NullObj
+}
+
+// Builtins call back into Monkey functions on behalf of their own call site
+func applyCallback(fn object.Object, args ...object.Object) object.Object {
+	if result := applyFunction(fn, args, builtinSite); result != nil {
+		return result
+	}
+
+	return NullObj
+}
+
+// Binds the arguments to the parameters. Missing arguments, which are nil when
+// skipped by keyword arguments, fall back to the default values.
 func extendFunctionEnv(fn *object.Function,
-	args []object.Object) *object.Environment {
-	env := object.NewEnclosedEnvironment(fn.Env)
+	args []object.Object) (*object.Environment, *object.Error) {
+	env := newScopeEnvironment(fn.Env, fn.Locals)
+	min, max := ast.ParametersArity(fn.Parameters, fn.Defaults, fn.Rest)
+
+	if err := checkArity(len(args), min, max); err != nil {
+		return nil, err
+	}
 
 	for i, param := range fn.Parameters {
-		env.Set(param.Value, args[i])
+		if i < len(args) && args[i] != nil {
+			bind(param, args[i], env)
+			continue
+		}
+
+		if i >= len(fn.Defaults) || fn.Defaults[i] == nil {
+			return nil, newError("missing argument: %s", param.Value)
+		}
+
+		value := Eval(fn.Defaults[i], env)
+
+		if errObj, ok := value.(*object.Error); ok {
+			return nil, errObj
+		}
+
+		bind(param, value, env)
 	}
 
-	return env
+	if fn.Rest != nil {
+		rest := []object.Object{}
+
+		if len(args) > len(fn.Parameters) {
+			rest = append(rest, args[len(fn.Parameters):]...)
+		}
+
+		bind(fn.Rest, &object.Array{Elements: rest}, env)
+	}
+
+	return env, nil
+}
+
+// Scopes get a frame if they have been resolved
+func newScopeEnvironment(outer *object.Environment, locals []string) *object.Environment {
+	if locals == nil {
+		return object.NewEnclosedEnvironment(outer)
+	}
+
+	return object.NewFrame(outer, locals)
+}
+
+func bind(ident *ast.Identifier, value object.Object, env *object.Environment) {
+	if ident.Resolved {
+		env.SetSlot(ident.Slot, value)
+	} else {
+		env.Set(ident.Value, value)
+	}
+}
+
+// Places keyword arguments at the position of their parameters, leaving gaps
+// for parameters which have not been passed
+func evalKeywordArguments(fn object.Object, args []object.Object, keywords []*ast.KeywordArgument, env *object.Environment) ([]object.Object, object.Object) {
+	function, ok := fn.(*object.Function)
+
+	if !ok {
+		return nil, newError("keyword arguments are not supported by %s", fn.Type())
+	}
+
+	if len(args) > len(function.Parameters) {
+		return nil, newError("wrong number of arguments. got %d, but expected at most %d before keyword arguments", len(args), len(function.Parameters))
+	}
+
+	for _, keyword := range keywords {
+		index := -1
+
+		for i, param := range function.Parameters {
+			if param.Value == keyword.Name.Value {
+				index = i
+			}
+		}
+
+		if index < 0 {
+			return nil, newError("unexpected keyword argument: %s", keyword.Name.Value)
+		}
+
+		for len(args) <= index {
+			args = append(args, nil)
+		}
+
+		if args[index] != nil {
+			return nil, newError("got multiple values for argument: %s", keyword.Name.Value)
+		}
+
+		value := Eval(keyword.Value, env)
+
+		if isError(value) {
+			return nil, value
+		}
+
+		args[index] = value
+	}
+
+	return args, nil
 }
 
 // Prevents return values from bubbleing up and stopping outer functions
@@ -363,7 +769,14 @@ func nativeBoolToBooleanObject(input bool) *object.Boolean {
 }
 
 func newError(format string, args ...any) *object.Error {
-	return &object.Error{Message: fmt.Sprintf(format, args...)}
+	return &object.Error{Kind: "RuntimeError", Message: fmt.Sprintf(format, args...)}
+}
+
+// Remembers the innermost statement an error was raised in
+func setErrorPosition(err *object.Error, stmt ast.Statement) {
+	if err.Position == (token.Position{}) {
+		err.Position = stmt.Pos()
+	}
 }
 
 func isError(obj object.Object) bool {
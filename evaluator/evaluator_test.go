@@ -3,10 +3,13 @@ package evaluator_test
 import (
 	"testing"
 
+	"github.com/henningstorck/monkey-interpreter/ast"
 	"github.com/henningstorck/monkey-interpreter/evaluator"
 	"github.com/henningstorck/monkey-interpreter/lexer"
 	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/optimizer"
 	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/henningstorck/monkey-interpreter/resolver"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -33,7 +36,7 @@ func TestEvalIntegerExpressions(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		evaluated := testEval(test.input)
+		evaluated := testEval(t, test.input)
 		testIntegerObject(t, evaluated, test.expected)
 	}
 }
@@ -65,7 +68,7 @@ func TestEvalBooleanExpressions(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		evaluated := testEval(test.input)
+		evaluated := testEval(t, test.input)
 		testBooleanObject(t, evaluated, test.expected)
 	}
 }
@@ -84,7 +87,7 @@ func TestEvalBangOperatorExpressions(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		evaluated := testEval(test.input)
+		evaluated := testEval(t, test.input)
 		testBooleanObject(t, evaluated, test.expected)
 	}
 }
@@ -101,10 +104,14 @@ func TestEvalIfExpressions(t *testing.T) {
 		{"if (1 > 2) { 10 }", nil},
 		{"if (1 > 2) { 10 } else { 20 }", 20},
 		{"if (1 < 2) { 10 } else { 20 }", 10},
+		{"if (1 > 2) { 10 } else if (1 < 2) { 20 } else { 30 }", 20},
+		{"if (1 > 2) { 10 } else if (1 > 2) { 20 } else { 30 }", 30},
+		{"if (1 > 2) { 10 } else if (1 > 2) { 20 }", nil},
+		{"let x = 3; if (x == 1) { 10 } else if (x == 2) { 20 } else if (x == 3) { 30 } else { 40 }", 30},
 	}
 
 	for _, test := range tests {
-		evaluated := testEval(test.input)
+		evaluated := testEval(t, test.input)
 		integer, ok := test.expected.(int)
 		if ok {
 			testIntegerObject(t, evaluated, int64(integer))
@@ -114,6 +121,35 @@ func TestEvalIfExpressions(t *testing.T) {
 	}
 }
 
+func TestEvalSwitchExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`switch (1) { case 1: "one" case 2: "two" }`, "one"},
+		{`switch (2) { case 1: "one"; case 2: "two"; }`, "two"},
+		{`switch (3) { case 1: "one" case 2: "two" }`, "null"},
+		{`switch (3) { case 1: "one" default: "other" }`, "other"},
+		{`switch (3) { default: "other" case 3: "three" }`, "three"},
+		{`switch (4) { case 1, 2: "small" case 3, 4: "big" }`, "big"},
+		{`switch ("b") { case "a": 1 case "b": 2 }`, "2"},
+		{`switch (true) { case 1 > 2: "a" case 1 < 2: "b" }`, "b"},
+		{`switch (1) { case 1: }`, "null"},
+		{`switch (1) { case 1: let x = 2; x * 3 }`, "6"},
+		{`let x = 2; switch (x + 1) { case x: "x" case x + 1: "x + 1" }`, "x + 1"},
+		{`let f = fn(x) { switch (x) { case 1: return "early"; default: 0 }; "late" }; [f(1), f(2)]`, "[early, late]"},
+		{`switch (1) { case 2: meow case 1: "one" }`, "one"},
+		{`switch (1) { case meow: "one" }`, "ERROR: identifier not found: meow"},
+		{`switch (meow) { default: 1 }`, "ERROR: identifier not found: meow"},
+		{`switch (1) { case 1: 1 + true }`, "ERROR: type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+	}
+}
+
 func TestEvalReturnStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -144,7 +180,7 @@ f(10);`, 20},
 	}
 
 	for _, test := range tests {
-		evaluated := testEval(test.input)
+		evaluated := testEval(t, test.input)
 		testIntegerObject(t, evaluated, test.expected)
 	}
 }
@@ -158,17 +194,18 @@ func TestEvalLetStatements(t *testing.T) {
 		{"let a = 5 * 5; a;", 25},
 		{"let a = 5; let b = a; b;", 5},
 		{"let a = 5; let b = a; let c = a + b + 5; c;", 15},
+		{"let a: int = 5; a;", 5},
 	}
 
 	for _, test := range tests {
-		evaluated := testEval(test.input)
+		evaluated := testEval(t, test.input)
 		testIntegerObject(t, evaluated, test.expected)
 	}
 }
 
 func TestEvalFunctionObject(t *testing.T) {
 	input := "fn(x) { x + 2; };"
-	evaluated := testEval(input)
+	evaluated := testEval(t, input)
 	fn, ok := evaluated.(*object.Function)
 	assert.True(t, ok)
 	assert.Len(t, fn.Parameters, 1)
@@ -190,11 +227,176 @@ func TestEvalFunctionApplication(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		evaluated := testEval(test.input)
+		evaluated := testEval(t, test.input)
 		testIntegerObject(t, evaluated, test.expected)
 	}
 }
 
+func TestEvalFunctionParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn(x, y = 10) { x + y }; f(1)", "11"},
+		{"let f = fn(x, y = 10) { x + y }; f(1, 2)", "3"},
+		{"let f = fn(x, y = x * 2) { y }; f(4)", "8"},
+		{"let n = 5; let f = fn(x = n) { x }; f()", "5"},
+		{"let f = fn(first, ...others) { others }; f(1, 2, 3)", "[2, 3]"},
+		{"let f = fn(first, ...others) { others }; f(1)", "[]"},
+		{"let f = fn(x = 0, ...others) { [x, others] }; f()", "[0, []]"},
+		{"let f = fn(a, b, c) { [a, b, c] }; let args = [1, 2, 3]; f(...args)", "[1, 2, 3]"},
+		{"let f = fn(a, b, c) { [a, b, c] }; f(1, ...[2, 3])", "[1, 2, 3]"},
+		{"let f = fn(...xs) { len(xs) }; f(...[1, 2], ...[3, 4])", "4"},
+		{"len(...[[1, 2]])", "2"},
+		{"[0, ...[1, 2], 3]", "[0, 1, 2, 3]"},
+		{"let f = fn(x, y = 1, z = 2) { [x, y, z] }; f(0, z: 5)", "[0, 1, 5]"},
+		{"let f = fn(x, y = 1, z = 2) { [x, y, z] }; f(z: 5, x: 3)", "[3, 1, 5]"},
+		{"let f = fn(x, y) { x - y }; f(y: 1, x: 3)", "2"},
+		{"fn(x, y = 10, ...rest) { 0 }", "fn(x, y = 10, ...rest) {\n0\n}"},
+		{"let f = fn(x: int, y: string = \"a\"): string { y }; f(1)", "a"},
+
+		{"let f = fn(x) { x }; f()", "ERROR: wrong number of arguments. got 0, but expected 1"},
+		{"let f = fn(x) { x }; f(1, 2)", "ERROR: wrong number of arguments. got 2, but expected 1"},
+		{"let f = fn(x, y = 1) { x }; f(1, 2, 3)", "ERROR: wrong number of arguments. got 3, but expected 1 to 2"},
+		{"let f = fn(x, y, ...z) { x }; f(1)", "ERROR: wrong number of arguments. got 1, but expected at least 2"},
+		{"let f = fn(x, y) { x }; f(y: 1)", "ERROR: missing argument: x"},
+		{"let f = fn(x) { x }; f(1, x: 2)", "ERROR: got multiple values for argument: x"},
+		{"let f = fn(x) { x }; f(x: 1, x: 2)", "ERROR: got multiple values for argument: x"},
+		{"let f = fn(x) { x }; f(y: 1)", "ERROR: unexpected keyword argument: y"},
+		{"let f = fn(x) { x }; f(1, 2, x: 1)", "ERROR: wrong number of arguments. got 2, but expected at most 1 before keyword arguments"},
+		{"len(value: [1])", "ERROR: keyword arguments are not supported by BUILTIN"},
+		{"let f = fn(x = meow) { x }; f()", "ERROR: identifier not found: meow"},
+		{"let f = fn(x) { x }; f(x: meow)", "ERROR: identifier not found: meow"},
+		{"let f = fn(x) { x }; f(...1)", "ERROR: cannot spread INTEGER, expected ARRAY"},
+		{"...[1]", "ERROR: spread is only allowed in calls and array literals"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+	}
+}
+
+func TestEvalFunctionDeclarations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn double(x) { x * 2 } double(4)", "8"},
+		{"let result = double(4); fn double(x) { x * 2 }; result", "8"},
+		{"fn fact(n) { if (n < 2) { 1 } else { n * fact(n - 1) } } fact(5)", "120"},
+		{`fn isEven(n) { if (n == 0) { true } else { isOdd(n - 1) } }
+fn isOdd(n) { if (n == 0) { false } else { isEven(n - 1) } }
+[isEven(10), isOdd(7)]`, "[true, true]"},
+		{`fn outer() { let result = helper(); fn helper() { 42 } result } outer()`, "42"},
+		{`fn outer() { fn inner() { 1 } inner() } inner`, "ERROR: identifier not found: inner"},
+		{`let f = fn() { g() }; fn g() { 7 } f()`, "7"},
+		{"fn add(x, y = 1) { x + y }; add", "fn add(x, y = 1) {\n(x + y)\n}"},
+		{"fn add(x, y) { x + y } help(add)", "fn add(x, y) {\n(x + y)\n}"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+	}
+}
+
+func TestEvalScopes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 1; let f = fn() { let x = 2; x }; [f(), x]", "[2, 1]"},
+		{"let f = fn(x) { fn(y) { fn(z) { x + y + z } } }; f(1)(2)(3)", "6"},
+		{"let f = fn() { let g = fn() { x }; let x = 5; g() }; f()", "5"},
+		{"let x = 1; let f = fn() { let y = x; let x = 2; [y, x] }; f()", "[1, 2]"},
+		{"let x = 1; let f = fn(c) { if (c) { let x = 2 }; x }; [f(true), f(false)]", "[2, 1]"},
+		{"let f = fn() { let a = fn(n) { if (n == 0) { 0 } else { b(n - 1) } }; let b = fn(n) { a(n) }; a(3) }; f()", "0"},
+		{"let f = fn(a, b = a * 2) { [a, b] }; f(3)", "[3, 6]"},
+		{"let x = 1; let f = fn(a = x) { let x = 2; a }; f()", "1"},
+		{"let f = fn(x) { match (x) { [a, b] => fn() { a + b }, _ => fn() { x } } }; [f([1, 2])(), f(3)()]", "[3, 3]"},
+		{"let f = fn() { try { throw 1 } catch(e) { let g = fn() { e.value }; g() } }; f()", "1"},
+		{"let f = fn() { try { throw 1 } catch(e) { 2 }; e }; f()", "ERROR: identifier not found: e"},
+		{"let counter = fn() { let count = 0; fn() { count + 1 } }; counter()()", "1"},
+		{"let f = fn(...xs) { let [first, ...others] = xs; [first, others] }; f(1, 2, 3)", "[1, [2, 3]]"},
+		{"let f = fn() { fn g() { h() } fn h() { 3 } g() }; f()", "3"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+		evaluated = testEvalUnresolved(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+	}
+}
+
+func TestEvalLambdasAndPipelines(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let double = x => x * 2; double(4)", "8"},
+		{"let add = (a, b) => a + b; add(1, 2)", "3"},
+		{"let answer = () => 42; answer()", "42"},
+		{"let adder = x => y => x + y; adder(1)(2)", "3"},
+		{"let count = (...xs) => len(xs); count(1, 2, 3)", "3"},
+		{"map([1, 2, 3], x => x * x)", "[1, 4, 9]"},
+		{"reduce([1, 2, 3], (sum, x) => sum + x, 0)", "6"},
+		{"[1, 2, 3] |> len", "3"},
+		{"[1, 2, 3] |> map(x => x * 10) |> reduce((a, b) => a + b)", "60"},
+		{"5 |> (x => x + 1)", "6"},
+		{"let f = fn(a, b = 2, c = 3) { [a, b, c] }; 1 |> f(c: 4)", "[1, 2, 4]"},
+		{`"a,b" |> split(",") |> join("-")`, "a-b"},
+		{"1 + 1 |> str", "2"},
+		{"let x = 5; match (x) { n if n > 3 => n * 2, _ => 0 }", "10"},
+		{"1 |> 2", "ERROR: not a function: INTEGER"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+	}
+}
+
+func TestEvalConditionalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"true ? 1 : 2", "1"},
+		{"false ? 1 : 2", "2"},
+		{"1 > 2 ? 1 : 0", "0"},
+		{`let sign = fn(n) { n > 0 ? "positive" : n < 0 ? "negative" : "zero" }; [sign(5), sign(-5), sign(0)]`, "[positive, negative, zero]"},
+		{"true ? 1 : meow", "1"},
+		{"false ? meow : 2", "2"},
+		{"first([]) ?? 5", "5"},
+		{"first([1]) ?? 5", "1"},
+		{"false ?? 5", "false"},
+		{"1 ?? meow", "1"},
+		{"first([]) ?? first([]) ?? 3", "3"},
+		{"let a = first([]); a?.[0]", "null"},
+		{"let a = [[1, 2]]; a?.[0]?.[1]", "2"},
+		{"let a = first([]); a?.[1:]", "null"},
+		{"[1, 2, 3]?.[1:]", "[2, 3]"},
+		{"let h = first([]); h?.name", "null"},
+		{`let h = {"name": "Ann"}; h?.name`, "Ann"},
+		{`let h = {"a": {}}; h.a?.b?.c ?? "none"`, "none"},
+		{"let a = first([]); a?.[meow]", "null"},
+
+		{"meow ? 1 : 2", "ERROR: identifier not found: meow"},
+		{"meow ?? 1", "ERROR: identifier not found: meow"},
+		{"first([]) ?? meow", "ERROR: identifier not found: meow"},
+		{"let h = first([]); h.name", "ERROR: member access is not supported: NULL"},
+		{"let h = first([]); h?.name.first", "ERROR: member access is not supported: NULL"},
+		{"5?.[0]", "ERROR: index operator is not supported: INTEGER"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+	}
+}
+
 func TestEvalClosures(t *testing.T) {
 	input := `let newAdder = fn(x) {
 	fn(y) { x + y };
@@ -202,12 +404,12 @@ func TestEvalClosures(t *testing.T) {
 let addTwo = newAdder(2);
 addTwo(2);`
 
-	testIntegerObject(t, testEval(input), 4)
+	testIntegerObject(t, testEval(t, input), 4)
 }
 
 func TestEvalStringLiterals(t *testing.T) {
 	input := `"hello world"`
-	evaluated := testEval(input)
+	evaluated := testEval(t, input)
 	str, ok := evaluated.(*object.String)
 	assert.True(t, ok)
 	assert.Equal(t, "hello world", str.Value)
@@ -215,7 +417,7 @@ func TestEvalStringLiterals(t *testing.T) {
 
 func TestEvalStringConcatenation(t *testing.T) {
 	input := `"hello" + " " + "world"`
-	evaluated := testEval(input)
+	evaluated := testEval(t, input)
 	str, ok := evaluated.(*object.String)
 	assert.True(t, ok)
 	assert.Equal(t, "hello world", str.Value)
@@ -223,7 +425,7 @@ func TestEvalStringConcatenation(t *testing.T) {
 
 func TestEvalArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3];"
-	evaluated := testEval(input)
+	evaluated := testEval(t, input)
 	arr, ok := evaluated.(*object.Array)
 	assert.True(t, ok)
 	assert.Len(t, arr.Elements, 3)
@@ -279,7 +481,7 @@ func TestEvalArrayIndexExpressions(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		evaluated := testEval(test.input)
+		evaluated := testEval(t, test.input)
 		integer, ok := test.expected.(int)
 
 		if ok {
@@ -299,6 +501,10 @@ func TestErrorHandling(t *testing.T) {
 			"5 + true;",
 			"type mismatch: INTEGER + BOOLEAN",
 		},
+		{
+			"let x = 0; 1 / x",
+			"division by zero",
+		},
 		{
 			"5 + true; 5;",
 			"type mismatch: INTEGER + BOOLEAN",
@@ -339,14 +545,81 @@ func TestErrorHandling(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		evaluated := testEval(test.input)
+		evaluated := testEval(t, test.input)
 		errObj, ok := evaluated.(*object.Error)
 		assert.True(t, ok)
 		assert.Equal(t, test.expectedMessage, errObj.Message)
 	}
 }
 
-func testEval(input string) object.Object {
+func TestErrorStackTrace(t *testing.T) {
+	input := `let add = fn(a, b) {
+	let sum = a + b;
+	sum;
+};
+let calc = fn(x) {
+	if (true) {
+		add(x, "one");
+	}
+};
+
+calc(1);`
+
+	evaluated := testEval(t, input)
+	errObj, ok := evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "type mismatch: INTEGER + STRING", errObj.Message)
+	assert.Equal(t, 2, errObj.Position.Line)
+	assert.Len(t, errObj.Stack, 2)
+	assert.Equal(t, "add", errObj.Stack[0].Function)
+	assert.Equal(t, 7, errObj.Stack[0].Position.Line)
+	assert.Equal(t, "calc", errObj.Stack[1].Function)
+	assert.Equal(t, 11, errObj.Stack[1].Position.Line)
+
+	expected := `ERROR: type mismatch: INTEGER + STRING
+	at add (2:2)
+	at calc (7:3)
+	at <main> (11:1)`
+
+	assert.Equal(t, expected, errObj.Traceback())
+}
+
+func TestErrorStackTraceNamedFunction(t *testing.T) {
+	evaluated := testEval(t, "fn negate(x) { -x } let alias = negate; alias(true)")
+	errObj, ok := evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "ERROR: unknown operator: -BOOLEAN\n\tat negate (1:16)\n\tat <main> (1:41)", errObj.Traceback())
+}
+
+func TestErrorStackTraceAnonymousFunction(t *testing.T) {
+	evaluated := testEval(t, "fn(x) { -x }(true)")
+	errObj, ok := evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "ERROR: unknown operator: -BOOLEAN\n\tat <anonymous> (1:9)\n\tat <main> (1:1)", errObj.Traceback())
+}
+
+// Evaluates the input with and without the optimizer, which has to give the
+// same result
+func testEval(t *testing.T, input string) object.Object {
+	evaluated := evalProgram(parse(input))
+	optimized := evalProgram(optimizer.Optimize(parse(input)))
+	assert.Equal(t, evaluated.Inspect(), optimized.Inspect(), "optimized: "+input)
+	return evaluated
+}
+
+func parse(input string) *ast.Program {
+	lex := lexer.NewLexer(input)
+	par := parser.NewParser(lex)
+	return par.ParseProgram()
+}
+
+func evalProgram(program *ast.Program) object.Object {
+	resolver.Resolve(program)
+	env := object.NewEnvironment()
+	return evaluator.Eval(program, env)
+}
+
+func testEvalUnresolved(input string) object.Object {
 	lex := lexer.NewLexer(input)
 	par := parser.NewParser(lex)
 	program := par.ParseProgram()
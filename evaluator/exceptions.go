@@ -0,0 +1,72 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+func evalTryExpression(tryExp *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(tryExp.Block, env)
+
+	if errObj, ok := result.(*object.Error); ok && tryExp.Catch != nil {
+		catchEnv := newScopeEnvironment(env, tryExp.CatchLocals)
+		bind(tryExp.Parameter, &object.Exception{Error: errObj}, catchEnv)
+		result = Eval(tryExp.Catch, catchEnv)
+	}
+
+	if tryExp.Finally != nil {
+		finally := Eval(tryExp.Finally, env)
+
+		if finally != nil && (finally.Type() == object.ErrorObj || finally.Type() == object.ReturnValueObj) {
+			return finally
+		}
+	}
+
+	if result == nil {
+		return NullObj
+	}
+
+	return result
+}
+
+// Rethrowing a caught exception keeps its original kind and stack
+func newThrownError(value object.Object) *object.Error {
+	switch value := value.(type) {
+	case *object.Exception:
+		return value.Error
+	case *object.String:
+		return &object.Error{Kind: "Error", Message: value.Value, Value: value}
+	default:
+		return &object.Error{Kind: "Error", Message: value.Inspect(), Value: value}
+	}
+}
+
+func evalExceptionIndexExpression(exception, index object.Object) object.Object {
+	errObj := exception.(*object.Exception).Error
+	key := index.(*object.String).Value
+
+	switch key {
+	case "message":
+		return &object.String{Value: errObj.Message}
+	case "type":
+		return &object.String{Value: errObj.Kind}
+	case "value":
+		if errObj.Value == nil {
+			return NullObj
+		}
+
+		return errObj.Value
+	case "stack":
+		frames := []object.Object{}
+
+		for _, frame := range errObj.Trace() {
+			frames = append(frames, &object.String{Value: fmt.Sprintf("%s (%s)", frame.Function, frame.Position)})
+		}
+
+		return &object.Array{Elements: frames}
+	default:
+		return NullObj
+	}
+}
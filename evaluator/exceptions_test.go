@@ -0,0 +1,83 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalTryExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{"try { 1 } catch (e) { 2 }", 1},
+		{"try { 1 + true } catch (e) { 2 }", 2},
+		{`try { throw "oops"; 1 } catch (e) { 2 }`, 2},
+		{`try { throw "oops" } catch (e) { e["message"] }`, "oops"},
+		{`try { throw "oops" } catch (e) { e["type"] }`, "Error"},
+		{`try { throw "oops" } catch (e) { e.message }`, "oops"},
+		{`try { 1 + true } catch (e) { e["message"] }`, "type mismatch: INTEGER + BOOLEAN"},
+		{`try { 1 + true } catch (e) { e["type"] }`, "RuntimeError"},
+		{`try { throw 42 } catch (e) { e["value"] }`, 42},
+		{`try { [1][2] + 1 } catch (e) { 0 }`, 0},
+		{`try { len(1) } catch (e) { -1 }`, -1},
+		{"let x = try { meow } catch (e) { 5 }; x * 2", 10},
+		{"let f = fn() { try { return 1 } finally { 2 } }; f()", 1},
+		{"let f = fn() { try { 1 } finally { return 2 } }; f()", 2},
+		{"try { 1 } finally { 2 }", 1},
+		{`try { throw "a" } catch (e) { throw "b" } finally { 3 }`, "ERROR: b"},
+		{`try { throw "a" } finally { 3 }`, "ERROR: a"},
+		{`try { 1 } finally { throw "b" }`, "ERROR: b"},
+		{`try { try { throw "a" } catch (e) { throw e } } catch (e) { e["message"] }`, "a"},
+		{"try { 1 + true } catch (e) { e }", "RuntimeError: type mismatch: INTEGER + BOOLEAN"},
+		{"try { 1 } catch (e) { }", 1},
+		{"try { } catch (e) { 2 }", nil},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			assert.Equal(t, expected, evaluated.Inspect())
+		case nil:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestEvalCaughtExceptionStack(t *testing.T) {
+	input := `let fail = fn() {
+	throw "oops";
+};
+
+try {
+	fail();
+} catch (e) {
+	e["stack"];
+}`
+
+	evaluated := testEval(t, input)
+	arr, ok := evaluated.(*object.Array)
+	assert.True(t, ok)
+	assert.Equal(t, "[fail (2:2), <main> (6:2)]", arr.Inspect())
+}
+
+func TestEvalUncaughtThrow(t *testing.T) {
+	input := `let fail = fn() {
+	throw "oops";
+};
+
+fail();
+5;`
+
+	evaluated := testEval(t, input)
+	errObj, ok := evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "oops", errObj.Message)
+	assert.Equal(t, "ERROR: oops\n\tat fail (2:2)\n\tat <main> (5:1)", errObj.Traceback())
+}
@@ -0,0 +1,48 @@
+package evaluator
+
+import (
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+func evalHashLiteral(hashLiteral *ast.HashLiteral, env *object.Environment) object.Object {
+	hash := object.NewHash()
+
+	for i, keyNode := range hashLiteral.Keys {
+		key := Eval(keyNode, env)
+
+		if isError(key) {
+			return key
+		}
+
+		hashKey, ok := key.(object.Hashable)
+
+		if !ok {
+			return newError("unusable as hash key: %s", key.Type())
+		}
+
+		value := Eval(hashLiteral.Values[i], env)
+
+		if isError(value) {
+			return value
+		}
+
+		hash.Set(hashKey, value)
+	}
+
+	return hash
+}
+
+func evalHashIndexExpression(hash, index object.Object) object.Object {
+	key, ok := index.(object.Hashable)
+
+	if !ok {
+		return newError("unusable as hash key: %s", index.Type())
+	}
+
+	if value, ok := hash.(*object.Hash).Get(key); ok {
+		return value
+	}
+
+	return NullObj
+}
@@ -0,0 +1,55 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalHashLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"one": 1, "two": 1 + 1}`, "{one: 1, two: 2}"},
+		{`let key = "three"; {key: 3, true: 4, 5: "five"}`, "{three: 3, true: 4, 5: five}"},
+		{`{"a": 1, "a": 2}`, "{a: 2}"},
+		{"{}", "{}"},
+		{`{[1]: 2}`, "ERROR: unusable as hash key: ARRAY"},
+		{`{"a": meow}`, "ERROR: identifier not found: meow"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+	}
+}
+
+func TestEvalHashIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`{"foo": 5}["foo"]`, 5},
+		{`{"foo": 5}["bar"]`, nil},
+		{`let key = "foo"; {"foo": 5}[key]`, 5},
+		{`{}["foo"]`, nil},
+		{`{5: 5}[5]`, 5},
+		{`{true: 5}[true]`, 5},
+		{`{false: 5}[false]`, 5},
+		{`{"foo": 5}.foo`, 5},
+		{`{"foo": {"bar": 6}}.foo.bar`, 6},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+
+		if expected, ok := test.expected.(int); ok {
+			testIntegerObject(t, evaluated, int64(expected))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+
+	assert.Equal(t, "ERROR: unusable as hash key: FUNCTION", testEval(t, `{"foo": 5}[fn(x) { x }]`).Inspect())
+}
@@ -0,0 +1,55 @@
+package evaluator
+
+import (
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+const (
+	minCachedInteger = -128
+	maxCachedInteger = 1024
+)
+
+// Integers are immutable, so small ones are shared instead of allocated for
+// every result
+var cachedIntegers = func() []*object.Integer {
+	integers := make([]*object.Integer, maxCachedInteger-minCachedInteger)
+
+	for i := range integers {
+		integers[i] = &object.Integer{Value: int64(i + minCachedInteger)}
+	}
+
+	return integers
+}()
+
+func newInteger(value int64) *object.Integer {
+	if value >= minCachedInteger && value < maxCachedInteger {
+		return cachedIntegers[value-minCachedInteger]
+	}
+
+	return &object.Integer{Value: value}
+}
+
+func evalIntegerLiteral(intLiteral *ast.IntegerLiteral) object.Object {
+	if integer, ok := intLiteral.Object.(*object.Integer); ok {
+		return integer
+	}
+
+	integer := newInteger(intLiteral.Value)
+	intLiteral.Object = integer
+	return integer
+}
+
+// Returns how many values a loop from start towards end visits with a step
+// which is not zero. The distance is unsigned, so it cannot overflow, while
+// start plus the step might.
+func steps(start, end, step int64) uint64 {
+	switch {
+	case step > 0 && start < end:
+		return (uint64(end-start)-1)/uint64(step) + 1
+	case step < 0 && start > end:
+		return (uint64(start-end)-1)/uint64(-step) + 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,169 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+)
+
+const moduleExtension = ".monkey"
+
+// SearchPath lists the directories imports are resolved against, after the
+// directory of the importing file.
+var SearchPath []string
+
+var (
+	modules      = map[string]*object.Module{}
+	loadingStack []string
+)
+
+// SetFile associates an environment with the file its program was loaded
+// from, so relative imports are resolved against the directory of that file.
+func SetFile(env *object.Environment, path string) {
+	if absPath, err := filepath.Abs(path); err == nil {
+		env.SetFile(absPath)
+	}
+}
+
+func evalImportExpression(path object.Object, env *object.Environment) object.Object {
+	pathObj, ok := path.(*object.String)
+
+	if !ok {
+		return newError("import path must be a STRING, got %s", path.Type())
+	}
+
+	resolved, ok := resolveModule(pathObj.Value, env)
+
+	if !ok {
+		return newError("module not found: %s", pathObj.Value)
+	}
+
+	if module, ok := modules[resolved]; ok {
+		return module
+	}
+
+	for i, loading := range loadingStack {
+		if loading == resolved {
+			cycle := append(append([]string{}, loadingStack[i:]...), resolved)
+			return newError("import cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	loadingStack = append(loadingStack, resolved)
+	defer func() { loadingStack = loadingStack[:len(loadingStack)-1] }()
+
+	return loadModule(resolved)
+}
+
+func loadModule(path string) object.Object {
+	source, err := os.ReadFile(path)
+
+	if err != nil {
+		return newError("could not read module %s: %s", path, err)
+	}
+
+	lex := lexer.NewLexer(string(source))
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+
+	if len(par.Errors()) != 0 {
+		return newError("could not parse module %s: %s", path, strings.Join(par.Errors(), ", "))
+	}
+
+	resolver.Resolve(program)
+	env := object.NewEnvironment()
+	env.SetFile(path)
+
+	if activeProfiler != nil {
+		profileEnter("<module>", path, 1)
+	}
+
+	result := Eval(program, env)
+
+	if activeProfiler != nil {
+		profileExit()
+	}
+
+	if isError(result) {
+		return result
+	}
+
+	module := &object.Module{Path: path, Exports: make(map[string]object.Object)}
+
+	// Exports skipped by a return are left out, as they were never bound
+	for _, stmt := range program.Statements {
+		if exportStmt, ok := stmt.(*ast.ExportStatement); ok {
+			for _, name := range exportStmt.Statement.Names() {
+				if value, ok := env.Get(name); ok {
+					module.Exports[name] = value
+				}
+			}
+		}
+	}
+
+	modules[path] = module
+	return module
+}
+
+func resolveModule(path string, env *object.Environment) (string, bool) {
+	candidates := []string{}
+
+	if filepath.IsAbs(path) {
+		candidates = append(candidates, path)
+	} else {
+		candidates = append(candidates, filepath.Join(importingDir(env), path))
+
+		for _, dir := range SearchPath {
+			candidates = append(candidates, filepath.Join(dir, path))
+		}
+	}
+
+	for _, candidate := range candidates {
+		for _, file := range []string{candidate, candidate + moduleExtension} {
+			if info, err := os.Stat(file); err == nil && !info.IsDir() {
+				absPath, err := filepath.Abs(file)
+				return absPath, err == nil
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Imports inside functions are resolved against the file the function was
+// defined in, which is found through the outermost environment
+func importingDir(env *object.Environment) string {
+	if path := FileOf(env); path != "" {
+		return filepath.Dir(path)
+	}
+
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// FileOf returns the file the code running in the environment was loaded from,
+// which is stored on the outermost environment.
+func FileOf(env *object.Environment) string {
+	for env.Outer() != nil {
+		env = env.Outer()
+	}
+
+	return env.File()
+}
+
+func evalModuleIndexExpression(module, index object.Object) object.Object {
+	moduleObj := module.(*object.Module)
+	key := index.(*object.String).Value
+
+	if value, ok := moduleObj.Exports[key]; ok {
+		return value
+	}
+
+	return newError("module %s does not export %s", filepath.Base(moduleObj.Path), key)
+}
@@ -0,0 +1,113 @@
+package evaluator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalImportExpressions(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"lib/math.monkey": `let square = fn(x) { x * x };
+export let double = fn(x) { x * 2 };
+export let sumOfSquares = fn(a, b) { square(a) + square(b) };`,
+		"lib/nested.monkey": `let math = import "math.monkey";
+export let quadruple = fn(x) { math.double(math.double(x)) };`,
+		"lib/reexport.monkey": `export let {double, sumOfSquares: sum} = import "math";`,
+		"lib/broken.monkey":   `export let value = 1 + true;`,
+		"lib/invalid.monkey":  `let = 1;`,
+		"lib/early.monkey":    `export let y = 2; return 1; export let x = 5;`,
+		"cycle/a.monkey":      `import "b.monkey";`,
+		"cycle/b.monkey":      `import "a.monkey";`,
+	})
+
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`let m = import "lib/math.monkey"; m.double(2)`, 4},
+		{`let m = import "lib/math"; m.sumOfSquares(2, 3)`, 13},
+		{`let m = import("lib/math"); m["double"](5)`, 10},
+		{`(import "lib/nested").quadruple(3)`, 12},
+		{`import "lib/math" == import "lib/math.monkey"`, true},
+		{`let {double} = import "lib/math"; double(6)`, 12},
+		{`let m = import "lib/reexport"; m.sum(1, 2) + m.double(1)`, 7},
+		{`let {square} = import "lib/math"; square`, "cannot destructure missing key: square"},
+		{`let m = import "lib/math"; m.square(2)`, "module math.monkey does not export square"},
+		{`let m = import "lib/early"; m.y + 1`, 3},
+		{`let m = import "lib/early"; m.x + 1`, "module early.monkey does not export x"},
+		{`let {x} = import "lib/early"; x`, "cannot destructure missing key: x"},
+		{`import "lib/missing"`, "module not found: lib/missing"},
+		{`import 5`, "import path must be a STRING, got INTEGER"},
+		{`import "lib/broken"`, "type mismatch: INTEGER + BOOLEAN"},
+		{`1.double`, "member access is not supported: INTEGER"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEvalFile(t, test.input, filepath.Join(dir, "main.monkey"))
+
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			assert.True(t, ok)
+			assert.Equal(t, expected, errObj.Message)
+		}
+	}
+
+	evaluated := testEvalFile(t, `import "lib/invalid"`, filepath.Join(dir, "main.monkey"))
+	errObj, ok := evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Contains(t, errObj.Message, "could not parse module")
+
+	evaluated = testEvalFile(t, `import "cycle/a"`, filepath.Join(dir, "main.monkey"))
+	errObj, ok = evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Contains(t, errObj.Message, "import cycle: ")
+	assert.Contains(t, errObj.Message, filepath.Join("cycle", "a.monkey")+" -> ")
+}
+
+func TestEvalImportSearchPath(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"lib/strings.monkey": `export let greeting = "hello";`,
+	})
+
+	evaluator.SearchPath = []string{filepath.Join(dir, "lib")}
+	defer func() { evaluator.SearchPath = nil }()
+
+	evaluated := testEvalFile(t, `(import "strings").greeting`, filepath.Join(t.TempDir(), "main.monkey"))
+	assert.Equal(t, "hello", evaluated.Inspect())
+}
+
+func writeModules(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	return dir
+}
+
+func testEvalFile(t *testing.T, input string, path string) object.Object {
+	lex := lexer.NewLexer(input)
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+	assert.Empty(t, par.Errors())
+	resolver.Resolve(program)
+	env := object.NewEnvironment()
+	evaluator.SetFile(env, path)
+	return evaluator.Eval(program, env)
+}
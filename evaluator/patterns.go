@@ -0,0 +1,201 @@
+package evaluator
+
+import (
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+var typeNames = map[object.ObjectType]string{
+	object.IntegerObj:   "int",
+	object.StringObj:    "string",
+	object.BooleanObj:   "bool",
+	object.ArrayObj:     "array",
+	object.HashObj:      "hash",
+	object.FunctionObj:  "fn",
+	object.BuiltinObj:   "fn",
+	object.NullObj:      "null",
+	object.ModuleObj:    "module",
+	object.ExceptionObj: "exception",
+}
+
+// Binds the names of a pattern to the matching parts of the value. A mismatch
+// describes why the value does not fit the pattern, while err is an error
+// raised while evaluating a default value.
+func matchPattern(pattern ast.Pattern, value object.Object, env *object.Environment) (mismatch, err *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if !ast.IsWildcard(pattern) {
+			bind(pattern, value, env)
+		}
+
+		return nil, nil
+	case *ast.IntegerLiteral, *ast.StringLiteral, *ast.BooleanLiteral:
+		return matchLiteralPattern(pattern, value), nil
+	case *ast.TypePattern:
+		if typeNames[value.Type()] != pattern.Type {
+			return newError("pattern mismatch: expected %s, got %s", pattern.Type, typeNames[value.Type()]), nil
+		}
+
+		return matchPattern(pattern.Target, value, env)
+	case *ast.DefaultPattern:
+		return matchPattern(pattern.Target, value, env)
+	case *ast.ArrayPattern:
+		return matchArrayPattern(pattern, value, env)
+	case *ast.HashPattern:
+		return matchHashPattern(pattern, value, env)
+	default:
+		return nil, newError("unknown pattern: %s", pattern.String())
+	}
+}
+
+// Binds a pattern like a let statement, so a mismatch is an error
+func bindPattern(pattern ast.Pattern, value object.Object, env *object.Environment) *object.Error {
+	mismatch, err := matchPattern(pattern, value, env)
+
+	if err != nil {
+		return err
+	}
+
+	return mismatch
+}
+
+func matchLiteralPattern(pattern ast.Pattern, value object.Object) *object.Error {
+	if !objectsEqual(Eval(pattern, nil), value) {
+		return newError("pattern mismatch: expected %s, got %s", pattern.String(), value.Inspect())
+	}
+
+	return nil
+}
+
+// Binds the default value of a pattern if there is one
+func matchMissing(pattern ast.Pattern, env *object.Environment) (ok bool, mismatch, err *object.Error) {
+	defaultPattern, ok := pattern.(*ast.DefaultPattern)
+
+	if !ok {
+		return false, nil, nil
+	}
+
+	value := Eval(defaultPattern.Default, env)
+
+	if errObj, ok := value.(*object.Error); ok {
+		return true, nil, errObj
+	}
+
+	mismatch, err = matchPattern(defaultPattern.Target, value, env)
+	return true, mismatch, err
+}
+
+func matchArrayPattern(pattern *ast.ArrayPattern, value object.Object, env *object.Environment) (mismatch, err *object.Error) {
+	arr, ok := value.(*object.Array)
+
+	if !ok {
+		return newError("cannot destructure %s as %s", value.Type(), object.ArrayObj), nil
+	}
+
+	count := len(pattern.Elements)
+
+	if len(arr.Elements) > count && pattern.Rest == nil {
+		return newError("too many values to destructure: expected %d, got %d", count, len(arr.Elements)), nil
+	}
+
+	for i, element := range pattern.Elements {
+		if i < len(arr.Elements) {
+			mismatch, err = matchPattern(element, arr.Elements[i], env)
+		} else if ok, mismatch, err = matchMissing(element, env); !ok {
+			mismatch = newError("not enough values to destructure: expected %d, got %d", count, len(arr.Elements))
+		}
+
+		if mismatch != nil || err != nil {
+			return mismatch, err
+		}
+	}
+
+	if pattern.Rest != nil {
+		rest := []object.Object{}
+
+		if len(arr.Elements) > count {
+			rest = append(rest, arr.Elements[count:]...)
+		}
+
+		bind(pattern.Rest, &object.Array{Elements: rest}, env)
+	}
+
+	return nil, nil
+}
+
+func matchHashPattern(pattern *ast.HashPattern, value object.Object, env *object.Environment) (mismatch, err *object.Error) {
+	lookup, mismatch := hashPatternLookup(value)
+
+	if mismatch != nil {
+		return mismatch, nil
+	}
+
+	for _, entry := range pattern.Entries {
+		if element, ok := lookup(entry.Key.Value); ok {
+			mismatch, err = matchPattern(entry.Value, element, env)
+		} else if ok, mismatch, err = matchMissing(entry.Value, env); !ok {
+			mismatch = newError("cannot destructure missing key: %s", entry.Key.Value)
+		}
+
+		if mismatch != nil || err != nil {
+			return mismatch, err
+		}
+	}
+
+	return nil, nil
+}
+
+// Hash patterns destructure hashes by their string keys and modules by their
+// exports
+func hashPatternLookup(value object.Object) (func(string) (object.Object, bool), *object.Error) {
+	switch value := value.(type) {
+	case *object.Hash:
+		return func(key string) (object.Object, bool) {
+			return value.Get(&object.String{Value: key})
+		}, nil
+	case *object.Module:
+		return func(key string) (object.Object, bool) {
+			element, ok := value.Exports[key]
+			return element, ok
+		}, nil
+	default:
+		return nil, newError("cannot destructure %s as %s", value.Type(), object.HashObj)
+	}
+}
+
+func evalMatchExpression(matchExp *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(matchExp.Subject, env)
+
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range matchExp.Arms {
+		armEnv := newScopeEnvironment(env, arm.Locals)
+		mismatch, err := matchPattern(arm.Pattern, subject, armEnv)
+
+		if err != nil {
+			return err
+		}
+
+		if mismatch != nil {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+
+			if isError(guard) {
+				return guard
+			}
+
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return Eval(arm.Body, armEnv)
+	}
+
+	return NullObj
+}
@@ -0,0 +1,89 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = [1, 2]; a + b", "3"},
+		{"let [a, ...rest] = [1, 2, 3]; rest", "[2, 3]"},
+		{"let [a, ...rest] = [1]; rest", "[]"},
+		{"let [a, b = 10] = [1]; b", "10"},
+		{"let [a, b = a * 2] = [3]; b", "6"},
+		{"let [a, b = 10] = [1, 2]; b", "2"},
+		{"let [[a, b], [c]] = [[1, 2], [3]]; a + b + c", "6"},
+		{"let divmod = fn(a, b) { [a / b, a - a / b * b] }; let [q, r] = divmod(7, 2); [q, r]", "[3, 1]"},
+
+		{`let {name, age} = {"name": "Alice", "age": 30}; name`, "Alice"},
+		{`let {name: n} = {"name": "Alice"}; n`, "Alice"},
+		{`let {name, age = 18} = {"name": "Bob"}; age`, "18"},
+		{`let {"first name": first} = {"first name": "Carol"}; first`, "Carol"},
+		{`let {address: {city}} = {"address": {"city": "Hamburg"}}; city`, "Hamburg"},
+		{`let {tags: [first, ...others]} = {"tags": [1, 2, 3]}; others`, "[2, 3]"},
+		{`let [{x}, {x: y}] = [{"x": 1}, {"x": 2}]; [x, y]`, "[1, 2]"},
+		{`let {missing = "none"} = {}; missing`, "none"},
+
+		{"let [_, b] = [1, 2]; _", "ERROR: identifier not found: _"},
+		{"let [1, b] = [1, 2]; b", "2"},
+		{"let [x: int] = [5]; x", "5"},
+
+		{"let [1, b] = [2, 2];", "ERROR: pattern mismatch: expected 1, got 2"},
+		{`let [x: int] = ["a"];`, "ERROR: pattern mismatch: expected int, got string"},
+		{"let [a, b] = 5;", "ERROR: cannot destructure INTEGER as ARRAY"},
+		{"let [a, b, c] = [1, 2];", "ERROR: not enough values to destructure: expected 3, got 2"},
+		{"let [a] = [1, 2];", "ERROR: too many values to destructure: expected 1, got 2"},
+		{`let {name} = [1];`, "ERROR: cannot destructure ARRAY as HASH"},
+		{`let {name} = {"age": 3};`, "ERROR: cannot destructure missing key: name"},
+		{`let {address: {city}} = {"address": "nowhere"};`, "ERROR: cannot destructure STRING as HASH"},
+		{"let [a = meow] = [];", "ERROR: identifier not found: meow"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+	}
+}
+
+func TestEvalMatchExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match (1) { 1 => "one", _ => "other" }`, "one"},
+		{`match (2) { 1 => "one", _ => "other" }`, "other"},
+		{`match (-3) { -3 => "minus three", _ => "other" }`, "minus three"},
+		{`match ("a") { "a" => 1, _ => 2 }`, "1"},
+		{`match (true) { false => 1, true => 2 }`, "2"},
+		{`match (5) { n => n * 2 }`, "10"},
+		{`match (5) { 1 => 1 }`, "null"},
+		{`match (5) { n if n > 10 => "big", n if n > 3 => "medium", _ => "small" }`, "medium"},
+		{`match ("x") { n: int => n + 1, s: string => s + "!", _ => "?" }`, "x!"},
+		{`match (len) { f: fn => "function", _ => "?" }`, "function"},
+		{`match (first([])) { _: null => "empty", _ => "?" }`, "empty"},
+		{`match ([1, 2, 3]) { [] => 0, [x] => x, [x, ...rest] => rest }`, "[2, 3]"},
+		{`match ([1, 2]) { [1, x] => x, _ => 0 }`, "2"},
+		{`match ([2, 2]) { [1, x] => x, _ => 0 }`, "0"},
+		{`match ([1, [2, 3]]) { [a, [b, c]] => a + b + c, _ => 0 }`, "6"},
+		{`match ({"type": "circle", "r": 2}) { {type: "square", side} => side * side, {type: "circle", r} => 3 * r * r }`, "12"},
+		{`match ({"name": "Bob"}) { {name, age} => age, {name} => name }`, "Bob"},
+		{`match ({"age": 3}) { {age: a: int} if a > 1 => "old", _ => "young" }`, "old"},
+		{`let x = 1; match (2) { x => x }; x`, "1"},
+		{`let f = fn(v) { match (v) { [] => "empty", [_] => "one", _: array => "many", _ => "not an array" } }; [f([]), f([1]), f([1, 2]), f(1)]`, "[empty, one, many, not an array]"},
+
+		{`match (meow) { _ => 1 }`, "ERROR: identifier not found: meow"},
+		{`match (1) { n if meow => 1 }`, "ERROR: identifier not found: meow"},
+		{`match ([]) { [a = meow] => 1 }`, "ERROR: identifier not found: meow"},
+		{`match (1) { 1 => 1 + true }`, "ERROR: type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+	}
+}
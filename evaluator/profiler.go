@@ -0,0 +1,126 @@
+package evaluator
+
+import (
+	"time"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/profile"
+)
+
+// A node of the calling context tree. Its children are the lines evaluated
+// after it in the same function and the functions called from it.
+type profileNode struct {
+	frame    profile.Frame
+	parent   *profileNode
+	children map[profile.Frame]*profileNode
+	calls    int64
+	count    int64
+	time     time.Duration
+}
+
+func (node *profileNode) child(frame profile.Frame) *profileNode {
+	if child, ok := node.children[frame]; ok {
+		return child
+	}
+
+	child := &profileNode{frame: frame, parent: node, children: make(map[profile.Frame]*profileNode)}
+	node.children[frame] = child
+	return child
+}
+
+// The time since the last event is attributed to the current node, which is
+// the line being evaluated
+type profiler struct {
+	root    *profileNode
+	current *profileNode
+	callers []*profileNode
+	start   time.Time
+	last    time.Time
+}
+
+var activeProfiler *profiler
+
+// StartProfile records the time spent and the calls made while evaluating
+// until StopProfile is called.
+func StartProfile() {
+	now := time.Now()
+	root := &profileNode{children: make(map[profile.Frame]*profileNode)}
+	activeProfiler = &profiler{root: root, current: root, start: now, last: now}
+}
+
+func StopProfile() *profile.Profile {
+	prof := activeProfiler
+
+	if prof == nil {
+		return &profile.Profile{}
+	}
+
+	prof.tick()
+	activeProfiler = nil
+	result := &profile.Profile{Start: prof.start, Duration: prof.last.Sub(prof.start)}
+	prof.collect(prof.root, &result.Samples)
+	return result
+}
+
+func (prof *profiler) tick() {
+	now := time.Now()
+	prof.current.time += now.Sub(prof.last)
+	prof.last = now
+}
+
+func (prof *profiler) collect(node *profileNode, samples *[]*profile.Sample) {
+	if node != prof.root && (node.calls != 0 || node.count != 0 || node.time != 0) {
+		sample := &profile.Sample{Calls: node.calls, Statements: node.count, Time: node.time}
+
+		for frame := node; frame != prof.root; frame = frame.parent {
+			sample.Stack = append(sample.Stack, frame.frame)
+		}
+
+		*samples = append(*samples, sample)
+	}
+
+	for _, child := range node.children {
+		prof.collect(child, samples)
+	}
+}
+
+// Moves to the line of the statement within the current function
+func profileStatement(node ast.Node, env *object.Environment) {
+	prof := activeProfiler
+	prof.tick()
+	frame := profile.Frame{Function: "<main>", File: FileOf(env)}
+	parent := prof.root
+
+	if prof.current != prof.root {
+		frame, parent = prof.current.frame, prof.current.parent
+	}
+
+	frame.Line = node.Pos().Line
+	prof.current = parent.child(frame)
+	prof.current.count++
+}
+
+func profileEnter(name string, file string, line int) {
+	prof := activeProfiler
+	prof.tick()
+	prof.callers = append(prof.callers, prof.current)
+	prof.current = prof.current.child(profile.Frame{Function: name, File: file, Line: line})
+	prof.current.calls++
+}
+
+func profileExit() {
+	prof := activeProfiler
+	prof.tick()
+	prof.current = prof.callers[len(prof.callers)-1]
+	prof.callers = prof.callers[:len(prof.callers)-1]
+}
+
+// Functions are entered at their first line
+func functionLine(fn *object.Function) int {
+	if fn.Body.Token.Line == 0 && len(fn.Body.Statements) > 0 {
+		return fn.Body.Statements[0].Pos().Line
+	}
+
+	return fn.Body.Token.Line
+}
@@ -0,0 +1,68 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/profile"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfile(t *testing.T) {
+	input := `let fib = fn(n) {
+	if (n < 2) { n } else { fib(n - 1) + fib(n - 2) }
+};
+let double = fn(x) {
+	x * 2
+};
+double(1) + double(2);
+fib(5)`
+
+	evaluator.StartProfile()
+	evaluated := evalProgram(parse(input))
+	prof := evaluator.StopProfile()
+	testIntegerObject(t, evaluated, 5)
+
+	calls := map[string]int64{}
+	statements := map[profile.Frame]int64{}
+
+	for _, sample := range prof.Samples {
+		leaf := sample.Stack[0]
+		calls[leaf.Function] += sample.Calls
+		statements[profile.Frame{Function: leaf.Function, Line: leaf.Line}] += sample.Statements
+		assert.Equal(t, "<main>", sample.Stack[len(sample.Stack)-1].Function)
+	}
+
+	assert.Equal(t, map[string]int64{"<main>": 0, "fib": 15, "double": 2}, calls)
+	// Both the if and the statement of the branch taken are on line 2
+	assert.Equal(t, int64(30), statements[profile.Frame{Function: "fib", Line: 2}])
+	assert.Equal(t, int64(2), statements[profile.Frame{Function: "double", Line: 5}])
+	assert.Equal(t, int64(1), statements[profile.Frame{Function: "<main>", Line: 8}])
+}
+
+func TestProfileStack(t *testing.T) {
+	input := `let inner = fn() {
+	1
+};
+let outer = fn() {
+	inner()
+};
+outer()`
+
+	evaluator.StartProfile()
+	evalProgram(parse(input))
+	prof := evaluator.StopProfile()
+	stacks := [][]profile.Frame{}
+
+	for _, sample := range prof.Samples {
+		if sample.Statements > 0 && sample.Stack[0].Function == "inner" {
+			stacks = append(stacks, sample.Stack)
+		}
+	}
+
+	assert.Equal(t, [][]profile.Frame{{
+		{Function: "inner", Line: 2},
+		{Function: "outer", Line: 5},
+		{Function: "<main>", Line: 7},
+	}}, stacks)
+}
@@ -0,0 +1,141 @@
+package evaluator
+
+import (
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+func evalSliceExpression(sliceExp *ast.SliceExpression, env *object.Environment) object.Object {
+	left := Eval(sliceExp.Left, env)
+
+	if isError(left) {
+		return left
+	}
+
+	if sliceExp.Optional && left == NullObj {
+		return NullObj
+	}
+
+	bounds := []object.Object{}
+
+	for _, exp := range []ast.Expression{sliceExp.Start, sliceExp.End, sliceExp.Step} {
+		if exp == nil {
+			bounds = append(bounds, nil)
+			continue
+		}
+
+		bound := Eval(exp, env)
+
+		if isError(bound) {
+			return bound
+		}
+
+		if bound.Type() != object.IntegerObj {
+			return newError("slice indices must be INTEGER, got %s", bound.Type())
+		}
+
+		bounds = append(bounds, bound)
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		indices, err := sliceIndices(len(left.Elements), bounds[0], bounds[1], bounds[2])
+
+		if err != nil {
+			return err
+		}
+
+		elements := make([]object.Object, len(indices))
+
+		for i, index := range indices {
+			elements[i] = left.Elements[index]
+		}
+
+		return &object.Array{Elements: elements}
+	case *object.String:
+		runes := []rune(left.Value)
+		indices, err := sliceIndices(len(runes), bounds[0], bounds[1], bounds[2])
+
+		if err != nil {
+			return err
+		}
+
+		result := make([]rune, len(indices))
+
+		for i, index := range indices {
+			result[i] = runes[index]
+		}
+
+		return &object.String{Value: string(result)}
+	default:
+		return newError("slice operator is not supported: %s", left.Type())
+	}
+}
+
+// Resolves the bounds of a slice the same way Python does: negative indices
+// count from the end, omitted bounds cover the whole sequence in the direction
+// of the step and out of range bounds are clamped.
+func sliceIndices(length int, start, end, step object.Object) ([]int, *object.Error) {
+	stepValue := 1
+
+	if step != nil {
+		stepValue = int(step.(*object.Integer).Value)
+	}
+
+	if stepValue == 0 {
+		return nil, newError("slice step must not be zero")
+	}
+
+	lower, upper := 0, length
+
+	if stepValue < 0 {
+		lower, upper = -1, length-1
+	}
+
+	startValue := sliceBound(start, length, lower, upper, stepValue > 0)
+	endValue := sliceBound(end, length, lower, upper, stepValue < 0)
+	indices := make([]int, steps(int64(startValue), int64(endValue), int64(stepValue)))
+
+	for n := range indices {
+		indices[n] = startValue + n*stepValue
+	}
+
+	return indices, nil
+}
+
+func sliceBound(bound object.Object, length, lower, upper int, useLower bool) int {
+	if bound == nil {
+		if useLower {
+			return lower
+		}
+
+		return upper
+	}
+
+	value := int(bound.(*object.Integer).Value)
+
+	if value < 0 {
+		value += length
+	}
+
+	if value < lower {
+		return lower
+	}
+
+	if value > upper {
+		return upper
+	}
+
+	return value
+}
+
+func evalStringIndexExpression(str, index object.Object) object.Object {
+	runes := []rune(str.(*object.String).Value)
+	indexValue := index.(*object.Integer).Value
+
+	if indexValue < 0 || indexValue >= int64(len(runes)) {
+		return NullObj
+	}
+
+	return &object.String{Value: string(runes[indexValue])}
+}
@@ -0,0 +1,55 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3, 4, 5][1:3]", "[2, 3]"},
+		{"[1, 2, 3, 4, 5][:2]", "[1, 2]"},
+		{"[1, 2, 3, 4, 5][3:]", "[4, 5]"},
+		{"[1, 2, 3, 4, 5][:]", "[1, 2, 3, 4, 5]"},
+		{"[1, 2, 3, 4, 5][-2:]", "[4, 5]"},
+		{"[1, 2, 3, 4, 5][:-2]", "[1, 2, 3]"},
+		{"[1, 2, 3, 4, 5][::2]", "[1, 3, 5]"},
+		{"[1, 2, 3, 4, 5][1::2]", "[2, 4]"},
+		{"[1, 2, 3, 4, 5][::-1]", "[5, 4, 3, 2, 1]"},
+		{"[1, 2, 3, 4, 5][3:0:-1]", "[4, 3, 2]"},
+		{"[1, 2, 3, 4, 5][-1:-4:-2]", "[5, 3]"},
+		{"[1, 2, 3, 4, 5][10:]", "[]"},
+		{"[1, 2, 3, 4, 5][-10:2]", "[1, 2]"},
+		{"[1, 2, 3, 4, 5][3:1]", "[]"},
+		{"[][:]", "[]"},
+		{"let a = [1, 2, 3]; let i = 1; a[i:i + 1]", "[2]"},
+
+		{`"hello"[1:3]`, "el"},
+		{`"hello"[::-1]`, "olleh"},
+		{`"äöü"[1:]`, "öü"},
+		{`"hello"[-3:]`, "llo"},
+
+		{`"hello"[1]`, "e"},
+		{`"äöü"[2]`, "ü"},
+		{`"hello"[5]`, "null"},
+		{`len("äöü")`, "3"},
+
+		{"[1, 2, 3][::0]", "ERROR: slice step must not be zero"},
+		{"[1, 2, 3][2::9223372036854775807]", "[3]"},
+		{"[1, 2, 3][0::9223372036854775807]", "[1]"},
+		{"[1, 2, 3][::-9223372036854775807]", "[3]"},
+		{"[1, 2, 3][-9223372036854775807:9223372036854775807:2]", "[1, 3]"},
+		{`[1, 2, 3]["a":]`, "ERROR: slice indices must be INTEGER, got STRING"},
+		{"5[1:2]", "ERROR: slice operator is not supported: INTEGER"},
+		{"[1, 2, 3][meow:]", "ERROR: identifier not found: meow"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), test.input)
+	}
+}
@@ -0,0 +1,323 @@
+package evaluator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+// Strings longer than this many bytes are not created by builtins
+const maxStringLength = 1 << 30
+
+func init() {
+	registerBuiltins(stringBuiltins)
+}
+
+var stringBuiltins = map[string]*object.Builtin{
+	"split": {
+		Signature: object.Signature{
+			Doc: "Splits a string at each occurrence of the separator.",
+			Parameters: []object.Parameter{
+				param("str", object.StringObj),
+				param("sep", object.StringObj),
+			},
+			Returns: object.ArrayObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			str := args[0].(*object.String).Value
+			sep := args[1].(*object.String).Value
+			return stringsToArray(strings.Split(str, sep))
+		},
+	},
+	"join": {
+		Signature: object.Signature{
+			Doc: "Concatenates the elements of an array, putting the separator between them.",
+			Parameters: []object.Parameter{
+				param("arr", object.ArrayObj),
+				param("sep", object.StringObj),
+			},
+			Returns: object.StringObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			elements := args[0].(*object.Array).Elements
+			parts := make([]string, len(elements))
+
+			for i, element := range elements {
+				parts[i] = element.Inspect()
+			}
+
+			return &object.String{Value: strings.Join(parts, args[1].(*object.String).Value)}
+		},
+	},
+	"trim": {
+		Signature: object.Signature{
+			Doc:        "Removes leading and trailing whitespace.",
+			Parameters: []object.Parameter{param("str", object.StringObj)},
+			Returns:    object.StringObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			return &object.String{Value: strings.TrimSpace(args[0].(*object.String).Value)}
+		},
+	},
+	"upper": {
+		Signature: object.Signature{
+			Doc:        "Converts a string to upper case.",
+			Parameters: []object.Parameter{param("str", object.StringObj)},
+			Returns:    object.StringObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			return &object.String{Value: strings.ToUpper(args[0].(*object.String).Value)}
+		},
+	},
+	"lower": {
+		Signature: object.Signature{
+			Doc:        "Converts a string to lower case.",
+			Parameters: []object.Parameter{param("str", object.StringObj)},
+			Returns:    object.StringObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			return &object.String{Value: strings.ToLower(args[0].(*object.String).Value)}
+		},
+	},
+	"contains": {
+		Signature: object.Signature{
+			Doc: "Reports whether the substring is within the string.",
+			Parameters: []object.Parameter{
+				param("str", object.StringObj),
+				param("substr", object.StringObj),
+			},
+			Returns: object.BooleanObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			str := args[0].(*object.String).Value
+			substr := args[1].(*object.String).Value
+			return nativeBoolToBooleanObject(strings.Contains(str, substr))
+		},
+	},
+	"starts_with": {
+		Signature: object.Signature{
+			Doc: "Reports whether the string begins with the prefix.",
+			Parameters: []object.Parameter{
+				param("str", object.StringObj),
+				param("prefix", object.StringObj),
+			},
+			Returns: object.BooleanObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			str := args[0].(*object.String).Value
+			prefix := args[1].(*object.String).Value
+			return nativeBoolToBooleanObject(strings.HasPrefix(str, prefix))
+		},
+	},
+	"ends_with": {
+		Signature: object.Signature{
+			Doc: "Reports whether the string ends with the suffix.",
+			Parameters: []object.Parameter{
+				param("str", object.StringObj),
+				param("suffix", object.StringObj),
+			},
+			Returns: object.BooleanObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			str := args[0].(*object.String).Value
+			suffix := args[1].(*object.String).Value
+			return nativeBoolToBooleanObject(strings.HasSuffix(str, suffix))
+		},
+	},
+	"index_of": {
+		Signature: object.Signature{
+			Doc: "Returns the character index of the first occurrence of the substring or -1.",
+			Parameters: []object.Parameter{
+				param("str", object.StringObj),
+				param("substr", object.StringObj),
+			},
+			Returns: object.IntegerObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			str := args[0].(*object.String).Value
+			index := strings.Index(str, args[1].(*object.String).Value)
+
+			if index < 0 {
+				return newInteger(-1)
+			}
+
+			return newInteger(int64(utf8.RuneCountInString(str[:index])))
+		},
+	},
+	"replace": {
+		Signature: object.Signature{
+			Doc: "Replaces all occurrences of old with new.",
+			Parameters: []object.Parameter{
+				param("str", object.StringObj),
+				param("old", object.StringObj),
+				param("new", object.StringObj),
+			},
+			Returns: object.StringObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			str := args[0].(*object.String).Value
+			old := args[1].(*object.String).Value
+			replacement := args[2].(*object.String).Value
+			return &object.String{Value: strings.ReplaceAll(str, old, replacement)}
+		},
+	},
+	"repeat": {
+		Signature: object.Signature{
+			Doc: "Returns the string repeated count times.",
+			Parameters: []object.Parameter{
+				param("str", object.StringObj),
+				param("count", object.IntegerObj),
+			},
+			Returns: object.StringObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			str := args[0].(*object.String).Value
+			count := args[1].(*object.Integer).Value
+
+			if count < 0 {
+				return newError("negative repeat count: %d", count)
+			}
+
+			if count > 0 && int64(len(str)) > maxStringLength/count {
+				return newError("repeat count too large: %d", count)
+			}
+
+			return &object.String{Value: strings.Repeat(str, int(count))}
+		},
+	},
+	"substr": {
+		Signature: object.Signature{
+			Doc: "Returns length characters starting at start, negative starts count from the end.",
+			Parameters: []object.Parameter{
+				param("str", object.StringObj),
+				param("start", object.IntegerObj),
+				optional("length", object.IntegerObj),
+			},
+			Returns: object.StringObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			runes := []rune(args[0].(*object.String).Value)
+			start := clampIndex(args[1].(*object.Integer).Value, len(runes))
+			end := len(runes)
+
+			if len(args) == 3 {
+				length := args[2].(*object.Integer).Value
+
+				if length < 0 {
+					return newError("negative substring length: %d", length)
+				}
+
+				// Clamped before adding, as the sum might overflow
+				if length > int64(len(runes)-start) {
+					length = int64(len(runes) - start)
+				}
+
+				end = start + int(length)
+			}
+
+			return &object.String{Value: string(runes[start:end])}
+		},
+	},
+	"chars": {
+		Signature: object.Signature{
+			Doc:        "Splits a string into its characters.",
+			Parameters: []object.Parameter{param("str", object.StringObj)},
+			Returns:    object.ArrayObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			chars := []string{}
+
+			for _, char := range args[0].(*object.String).Value {
+				chars = append(chars, string(char))
+			}
+
+			return stringsToArray(chars)
+		},
+	},
+	"ord": {
+		Signature: object.Signature{
+			Doc:        "Returns the code point of a single character.",
+			Parameters: []object.Parameter{param("char", object.StringObj)},
+			Returns:    object.IntegerObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			runes := []rune(args[0].(*object.String).Value)
+
+			if len(runes) != 1 {
+				return newError("expected a single character, got %d", len(runes))
+			}
+
+			return newInteger(int64(runes[0]))
+		},
+	},
+	"chr": {
+		Signature: object.Signature{
+			Doc:        "Returns the character of a code point.",
+			Parameters: []object.Parameter{param("code", object.IntegerObj)},
+			Returns:    object.StringObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			code := args[0].(*object.Integer).Value
+
+			if code < 0 || code > utf8.MaxRune || !utf8.ValidRune(rune(code)) {
+				return newError("invalid character code: %d", code)
+			}
+
+			return &object.String{Value: string(rune(code))}
+		},
+	},
+	"str": {
+		Signature: object.Signature{
+			Doc:        "Converts any value to a string.",
+			Parameters: []object.Parameter{param("value")},
+			Returns:    object.StringObj,
+		},
+		Function: func(args ...object.Object) object.Object {
+			if str, ok := args[0].(*object.String); ok {
+				return str
+			}
+
+			return &object.String{Value: args[0].Inspect()}
+		},
+	},
+}
+
+func stringsToArray(values []string) *object.Array {
+	elements := make([]object.Object, len(values))
+
+	for i, value := range values {
+		elements[i] = &object.String{Value: value}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// Negative indices count from the end, out of range indices are clamped
+func clampIndex(index int64, length int) int {
+	if index < 0 {
+		index += int64(length)
+	}
+
+	if index < 0 {
+		return 0
+	}
+
+	if index > int64(length) {
+		return length
+	}
+
+	return int(index)
+}
+
+// Strings are immutable, so a literal evaluates to the same object every time
+func evalStringLiteral(stringLiteral *ast.StringLiteral) object.Object {
+	if str, ok := stringLiteral.Object.(*object.String); ok {
+		return str
+	}
+
+	str := &object.String{Value: stringLiteral.Value}
+	stringLiteral.Object = str
+	return str
+}
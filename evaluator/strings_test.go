@@ -0,0 +1,104 @@
+package evaluator_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalStringBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`split("a,b,c", ",")`, []string{"a", "b", "c"}},
+		{`split("abc", "")`, []string{"a", "b", "c"}},
+		{`split("abc", 1)`, "invalid argument. got INTEGER, but expected STRING"},
+		{`split("abc")`, "wrong number of arguments. got 1, but expected 2"},
+
+		{`join(["a", "b", "c"], "-")`, "a-b-c"},
+		{`join([1, true, "x"], ", ")`, "1, true, x"},
+		{`join([], ",")`, ""},
+		{`join("abc", ",")`, "invalid argument. got STRING, but expected ARRAY"},
+
+		{"trim(\"  hello \t\")", "hello"},
+		{`upper("Hello")`, "HELLO"},
+		{`lower("Hello")`, "hello"},
+		{`upper(1)`, "invalid argument. got INTEGER, but expected STRING"},
+
+		{`contains("hello", "ell")`, true},
+		{`contains("hello", "xyz")`, false},
+		{`starts_with("hello", "he")`, true},
+		{`starts_with("hello", "lo")`, false},
+		{`ends_with("hello", "lo")`, true},
+		{`ends_with("hello", "he")`, false},
+
+		{`index_of("hello", "l")`, 2},
+		{`index_of("hello", "z")`, -1},
+		{`index_of("äöü", "ü")`, 2},
+
+		{`replace("a-b-c", "-", "+")`, "a+b+c"},
+		{`repeat("ab", 3)`, "ababab"},
+		{`repeat("ab", 0)`, ""},
+		{`repeat("ab", -1)`, "negative repeat count: -1"},
+		{`repeat("ab", 9223372036854775807)`, "repeat count too large: 9223372036854775807"},
+		{`repeat("", 9223372036854775807)`, ""},
+		{`repeat(3, "ab")`, "invalid argument. got INTEGER, but expected STRING"},
+
+		{`substr("hello", 1)`, "ello"},
+		{`substr("hello", 1, 3)`, "ell"},
+		{`substr("hello", -3)`, "llo"},
+		{`substr("hello", 3, 10)`, "lo"},
+		{`substr("abc", 1, 9223372036854775807)`, "bc"},
+		{`substr("hello", 10)`, ""},
+		{`substr("äöü", 1, 1)`, "ö"},
+		{`substr("hello", 1, -1)`, "negative substring length: -1"},
+		{`substr("hello")`, "wrong number of arguments. got 1, but expected 2 to 3"},
+		{`substr("hello", "1")`, "invalid argument. got STRING, but expected INTEGER"},
+
+		{`chars("aäb")`, []string{"a", "ä", "b"}},
+		{`chars("")`, []string{}},
+		{`ord("a")`, 97},
+		{`ord("ä")`, 228},
+		{`ord("ab")`, "expected a single character, got 2"},
+		{`chr(97)`, "a"},
+		{`chr(-1)`, "invalid character code: -1"},
+
+		{`str(42)`, "42"},
+		{`str(true)`, "true"},
+		{`str([1, "a"])`, "[1, a]"},
+		{`str("a")`, "a"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(t, test.input)
+
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			if errObj, ok := evaluated.(*object.Error); ok {
+				assert.Equal(t, expected, errObj.Message)
+			} else {
+				testStringObject(t, evaluated, expected)
+			}
+		case []string:
+			arrObj, ok := evaluated.(*object.Array)
+			assert.True(t, ok)
+			assert.Len(t, arrObj.Elements, len(expected))
+
+			for i, expectedItem := range expected {
+				testStringObject(t, arrObj.Elements[i], expectedItem)
+			}
+		}
+	}
+}
+
+func testStringObject(t *testing.T, obj object.Object, expected string) {
+	result, ok := obj.(*object.String)
+	assert.True(t, ok)
+	assert.Equal(t, expected, result.Value)
+}
@@ -0,0 +1,90 @@
+package evaluator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+// Lines are indented by the number of function calls being evaluated
+type tracer struct {
+	out   io.Writer
+	depth int
+}
+
+var activeTracer *tracer
+
+// SetTrace writes a line to w for each node after it is evaluated, for each
+// function call and return and for each binding created by let. Tracing stops
+// if w is nil.
+func SetTrace(w io.Writer) {
+	if w == nil {
+		activeTracer = nil
+		return
+	}
+
+	activeTracer = &tracer{out: w}
+}
+
+func (trace *tracer) printf(format string, args ...any) {
+	fmt.Fprintf(trace.out, strings.Repeat("  ", trace.depth)+format+"\n", args...)
+}
+
+func traceNode(node ast.Node, env *object.Environment) object.Object {
+	result := eval(node, env)
+	kind := strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+
+	if result == nil {
+		activeTracer.printf("%s %s", kind, node.Pos())
+	} else {
+		activeTracer.printf("%s %s => %s", kind, node.Pos(), traceValue(result))
+	}
+
+	return result
+}
+
+func traceLet(letStmt *ast.LetStatement, env *object.Environment) {
+	for _, name := range letStmt.Names() {
+		if value, ok := env.Get(name); ok {
+			activeTracer.printf("let %s = %s", name, traceValue(value))
+		}
+	}
+}
+
+// Arguments are shown as they were bound to the parameters, including
+// default values
+func traceCall(name string, fn *object.Function, env *object.Environment) {
+	args := []string{}
+	params := fn.Parameters
+
+	if fn.Rest != nil {
+		params = append(params[:len(params):len(params)], fn.Rest)
+	}
+
+	for _, param := range params {
+		if value, ok := env.Get(param.Value); ok {
+			args = append(args, param.Value+": "+traceValue(value))
+		}
+	}
+
+	activeTracer.printf("call %s(%s)", name, strings.Join(args, ", "))
+	activeTracer.depth++
+}
+
+func traceReturn(name string, result object.Object) {
+	activeTracer.depth--
+
+	if result == nil {
+		result = NullObj
+	}
+
+	activeTracer.printf("return %s => %s", name, traceValue(result))
+}
+
+// Each event takes one line, even for functions
+func traceValue(obj object.Object) string {
+	return strings.ReplaceAll(obj.Inspect(), "\n", " ")
+}
@@ -0,0 +1,59 @@
+package evaluator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	input := `let double = fn(x, y = 2) { x * y };
+let [a] = [double(3)];
+a`
+
+	var out strings.Builder
+	evaluator.SetTrace(&out)
+	evaluated := evalProgram(parse(input))
+	evaluator.SetTrace(nil)
+	testIntegerObject(t, evaluated, 6)
+
+	expected := `FunctionLiteral 1:14 => fn(x, y = 2) { (x * y) }
+let double = fn(x, y = 2) { (x * y) }
+LetStatement 1:1
+Identifier 2:12 => fn(x, y = 2) { (x * y) }
+IntegerLiteral 2:19 => 3
+IntegerLiteral 1:24 => 2
+call double(x: 3, y: 2)
+  Identifier 1:29 => 3
+  Identifier 1:33 => 2
+  InfixExpression 1:31 => 6
+  ExpressionStatement 1:29 => 6
+  BlockStatement 1:27 => 6
+return double => 6
+CallExpression 2:18 => 6
+ArrayLiteral 2:11 => [6]
+let a = 6
+LetStatement 2:1
+Identifier 3:1 => 6
+ExpressionStatement 3:1 => 6
+Program 1:1 => 6
+`
+
+	assert.Equal(t, expected, out.String())
+}
+
+func TestTraceRestAndErrors(t *testing.T) {
+	input := `let f = fn(a, ...rest) { a + true };
+f(1, 2, 3)`
+
+	var out strings.Builder
+	evaluator.SetTrace(&out)
+	evalProgram(parse(input))
+	evaluator.SetTrace(nil)
+
+	assert.Contains(t, out.String(), "call f(a: 1, rest: [2, 3])\n")
+	assert.Contains(t, out.String(), "  InfixExpression 1:28 => ERROR: type mismatch: INTEGER + BOOLEAN\n")
+	assert.Contains(t, out.String(), "return f => ERROR: type mismatch: INTEGER + BOOLEAN\n")
+}
@@ -0,0 +1,24 @@
+package lexer_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/benchmarks"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+func BenchmarkLexer(b *testing.B) {
+	for _, program := range benchmarks.Programs() {
+		b.Run(program.Name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				lex := lexer.NewLexer(program.Source)
+
+				for tok := lex.NextToken(); tok.Type != token.EOF; tok = lex.NextToken() {
+				}
+			}
+		})
+	}
+}
@@ -1,21 +1,34 @@
 package lexer
 
-import "github.com/henningstorck/monkey-interpreter/token"
+import (
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/token"
+)
 
 type Lexer struct {
 	input        string
 	position     int
 	readPosition int
 	char         byte
+	line         int
+	column       int
 }
 
 func NewLexer(input string) *Lexer {
-	lex := &Lexer{input: input}
+	lex := &Lexer{input: input, line: 1}
 	lex.readChar()
 	return lex
 }
 
 func (lex *Lexer) readChar() {
+	if lex.char == '\n' {
+		lex.line++
+		lex.column = 0
+	}
+
+	lex.column++
+
 	if lex.readPosition >= len(lex.input) {
 		lex.char = 0
 	} else {
@@ -34,10 +47,21 @@ func (lex *Lexer) peekChar() byte {
 	}
 }
 
+func (lex *Lexer) peekCharAt(offset int) byte {
+	position := lex.position + offset
+
+	if position >= len(lex.input) {
+		return 0
+	}
+
+	return lex.input[position]
+}
+
 func (lex *Lexer) NextToken() token.Token {
 	var tok token.Token
 
 	lex.skipWhitespace()
+	position := token.Position{Line: lex.line, Column: lex.column}
 
 	switch lex.char {
 	case '=':
@@ -46,6 +70,11 @@ func (lex *Lexer) NextToken() token.Token {
 			lex.readChar()
 			tok.Literal = string(char) + string(lex.char)
 			tok.Type = token.Eq
+		} else if lex.peekChar() == '>' {
+			char := lex.char
+			lex.readChar()
+			tok.Literal = string(char) + string(lex.char)
+			tok.Type = token.Arrow
 		} else {
 			tok = token.NewToken(token.Assign, lex.char)
 		}
@@ -65,15 +94,55 @@ func (lex *Lexer) NextToken() token.Token {
 	case '*':
 		tok = token.NewToken(token.Asterisk, lex.char)
 	case '/':
+		if lex.peekChar() == '/' {
+			tok.Literal = lex.readComment()
+			tok.Type = token.Comment
+			tok.Position = position
+			return tok
+		}
+
 		tok = token.NewToken(token.Slash, lex.char)
 	case '<':
 		tok = token.NewToken(token.LessThan, lex.char)
 	case '>':
 		tok = token.NewToken(token.GreaterThan, lex.char)
+	case '|':
+		if lex.peekChar() == '>' {
+			char := lex.char
+			lex.readChar()
+			tok.Literal = string(char) + string(lex.char)
+			tok.Type = token.Pipe
+		} else {
+			tok = token.NewToken(token.Illegal, lex.char)
+		}
+	case '?':
+		if lex.peekChar() == '?' || lex.peekChar() == '.' {
+			char := lex.char
+			lex.readChar()
+			tok.Literal = string(char) + string(lex.char)
+			tok.Type = token.NullCoalesce
+
+			if lex.char == '.' {
+				tok.Type = token.OptionalChain
+			}
+		} else {
+			tok = token.NewToken(token.Question, lex.char)
+		}
 	case ';':
 		tok = token.NewToken(token.Semicolon, lex.char)
 	case ',':
 		tok = token.NewToken(token.Comma, lex.char)
+	case '.':
+		if lex.peekChar() == '.' && lex.peekCharAt(2) == '.' {
+			lex.readChar()
+			lex.readChar()
+			tok.Literal = "..."
+			tok.Type = token.Ellipsis
+		} else {
+			tok = token.NewToken(token.Dot, lex.char)
+		}
+	case ':':
+		tok = token.NewToken(token.Colon, lex.char)
 	case '(':
 		tok = token.NewToken(token.LParen, lex.char)
 	case ')':
@@ -96,16 +165,19 @@ func (lex *Lexer) NextToken() token.Token {
 		if isLetter(lex.char) {
 			tok.Literal = lex.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
+			tok.Position = position
 			return tok
 		} else if isDigit(lex.char) {
 			tok.Literal = lex.readNumber()
 			tok.Type = token.Int
+			tok.Position = position
 			return tok
 		} else {
 			tok = token.NewToken(token.Illegal, lex.char)
 		}
 	}
 
+	tok.Position = position
 	lex.readChar()
 	return tok
 }
@@ -150,6 +222,17 @@ func (lex *Lexer) readString() string {
 	return lex.input[position:lex.position]
 }
 
+// Comments run until the end of the line
+func (lex *Lexer) readComment() string {
+	position := lex.position
+
+	for lex.char != '\n' && lex.char != 0 {
+		lex.readChar()
+	}
+
+	return strings.TrimRight(lex.input[position:lex.position], "\r")
+}
+
 func isLetter(char byte) bool {
 	return 'a' <= char && char <= 'z' || 'A' <= char && char <= 'Z' || char == '_'
 }
@@ -177,6 +177,104 @@ func TestNextTokenMoreKeywords(t *testing.T) {
 	}
 }
 
+func TestNextTokenExceptionKeywords(t *testing.T) {
+	input := `try { throw "oops"; } catch (e) { } finally { }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.Try, "try"},
+		{token.LBrace, "{"},
+		{token.Throw, "throw"},
+		{token.String, "oops"},
+		{token.Semicolon, ";"},
+		{token.RBrace, "}"},
+		{token.Catch, "catch"},
+		{token.LParen, "("},
+		{token.Ident, "e"},
+		{token.RParen, ")"},
+		{token.LBrace, "{"},
+		{token.RBrace, "}"},
+		{token.Finally, "finally"},
+		{token.LBrace, "{"},
+		{token.RBrace, "}"},
+		{token.EOF, ""},
+	}
+
+	lex := lexer.NewLexer(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		assert.Equal(t, test.expectedType, tok.Type)
+		assert.Equal(t, test.expectedLiteral, tok.Literal)
+	}
+}
+
+func TestNextTokenModules(t *testing.T) {
+	input := `export let m = import "lib"; m.add;`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.Export, "export"},
+		{token.Let, "let"},
+		{token.Ident, "m"},
+		{token.Assign, "="},
+		{token.Import, "import"},
+		{token.String, "lib"},
+		{token.Semicolon, ";"},
+		{token.Ident, "m"},
+		{token.Dot, "."},
+		{token.Ident, "add"},
+		{token.Semicolon, ";"},
+		{token.EOF, ""},
+	}
+
+	lex := lexer.NewLexer(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		assert.Equal(t, test.expectedType, tok.Type)
+		assert.Equal(t, test.expectedLiteral, tok.Literal)
+	}
+}
+
+func TestNextTokenSwitchKeywords(t *testing.T) {
+	input := `switch (x) { case 1, 2: a default: b }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.Switch, "switch"},
+		{token.LParen, "("},
+		{token.Ident, "x"},
+		{token.RParen, ")"},
+		{token.LBrace, "{"},
+		{token.Case, "case"},
+		{token.Int, "1"},
+		{token.Comma, ","},
+		{token.Int, "2"},
+		{token.Colon, ":"},
+		{token.Ident, "a"},
+		{token.Default, "default"},
+		{token.Colon, ":"},
+		{token.Ident, "b"},
+		{token.RBrace, "}"},
+		{token.EOF, ""},
+	}
+
+	lex := lexer.NewLexer(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		assert.Equal(t, test.expectedType, tok.Type)
+		assert.Equal(t, test.expectedLiteral, tok.Literal)
+	}
+}
+
 func TestNextTokenComposedOperators(t *testing.T) {
 	input := `10 == 10;
 10 != 9;`
@@ -236,7 +334,7 @@ func TestNextTokenStrings(t *testing.T) {
 }
 
 func TestNextTokenArrays(t *testing.T) {
-	input := "[1, 2];"
+	input := "[1, 2]; a[:2]"
 
 	tests := []struct {
 		expectedType    token.TokenType
@@ -248,6 +346,134 @@ func TestNextTokenArrays(t *testing.T) {
 		{token.Int, "2"},
 		{token.RBracket, "]"},
 		{token.Semicolon, ";"},
+		{token.Ident, "a"},
+		{token.LBracket, "["},
+		{token.Colon, ":"},
+		{token.Int, "2"},
+		{token.RBracket, "]"},
+		{token.EOF, ""},
+	}
+
+	lex := lexer.NewLexer(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		assert.Equal(t, test.expectedType, tok.Type)
+		assert.Equal(t, test.expectedLiteral, tok.Literal)
+	}
+}
+
+func TestNextTokenPositions(t *testing.T) {
+	input := `let x = 5;
+	"meow" == x;`
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"let", 1, 1},
+		{"x", 1, 5},
+		{"=", 1, 7},
+		{"5", 1, 9},
+		{";", 1, 10},
+		{"meow", 2, 2},
+		{"==", 2, 9},
+		{"x", 2, 12},
+		{";", 2, 13},
+		{"", 2, 14},
+	}
+
+	lex := lexer.NewLexer(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		assert.Equal(t, test.expectedLiteral, tok.Literal)
+		assert.Equal(t, test.expectedLine, tok.Line)
+		assert.Equal(t, test.expectedColumn, tok.Column)
+	}
+}
+
+func TestNextTokenEllipsis(t *testing.T) {
+	input := "let [a, ...rest] = x.y;"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.Let, "let"},
+		{token.LBracket, "["},
+		{token.Ident, "a"},
+		{token.Comma, ","},
+		{token.Ellipsis, "..."},
+		{token.Ident, "rest"},
+		{token.RBracket, "]"},
+		{token.Assign, "="},
+		{token.Ident, "x"},
+		{token.Dot, "."},
+		{token.Ident, "y"},
+		{token.Semicolon, ";"},
+		{token.EOF, ""},
+	}
+
+	lex := lexer.NewLexer(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		assert.Equal(t, test.expectedType, tok.Type)
+		assert.Equal(t, test.expectedLiteral, tok.Literal)
+	}
+}
+
+func TestNextTokenMatch(t *testing.T) {
+	input := `match (x) { 1 => a, _ => b }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.Match, "match"},
+		{token.LParen, "("},
+		{token.Ident, "x"},
+		{token.RParen, ")"},
+		{token.LBrace, "{"},
+		{token.Int, "1"},
+		{token.Arrow, "=>"},
+		{token.Ident, "a"},
+		{token.Comma, ","},
+		{token.Ident, "_"},
+		{token.Arrow, "=>"},
+		{token.Ident, "b"},
+		{token.RBrace, "}"},
+		{token.EOF, ""},
+	}
+
+	lex := lexer.NewLexer(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		assert.Equal(t, test.expectedType, tok.Type)
+		assert.Equal(t, test.expectedLiteral, tok.Literal)
+	}
+}
+
+func TestNextTokenLambdasAndPipelines(t *testing.T) {
+	input := "xs |> map(x => x) | y"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.Ident, "xs"},
+		{token.Pipe, "|>"},
+		{token.Ident, "map"},
+		{token.LParen, "("},
+		{token.Ident, "x"},
+		{token.Arrow, "=>"},
+		{token.Ident, "x"},
+		{token.RParen, ")"},
+		{token.Illegal, "|"},
+		{token.Ident, "y"},
 		{token.EOF, ""},
 	}
 
@@ -259,3 +485,62 @@ func TestNextTokenArrays(t *testing.T) {
 		assert.Equal(t, test.expectedLiteral, tok.Literal)
 	}
 }
+
+func TestNextTokenConditionals(t *testing.T) {
+	input := "a ? b : c ?? d?.e?.[0]"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.Ident, "a"},
+		{token.Question, "?"},
+		{token.Ident, "b"},
+		{token.Colon, ":"},
+		{token.Ident, "c"},
+		{token.NullCoalesce, "??"},
+		{token.Ident, "d"},
+		{token.OptionalChain, "?."},
+		{token.Ident, "e"},
+		{token.OptionalChain, "?."},
+		{token.LBracket, "["},
+		{token.Int, "0"},
+		{token.RBracket, "]"},
+		{token.EOF, ""},
+	}
+
+	lex := lexer.NewLexer(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		assert.Equal(t, test.expectedType, tok.Type)
+		assert.Equal(t, test.expectedLiteral, tok.Literal)
+	}
+}
+
+func TestNextTokenComments(t *testing.T) {
+	input := "1 / 2 // half\r\n// \"not a string\"\n3"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLine    int
+	}{
+		{token.Int, "1", 1},
+		{token.Slash, "/", 1},
+		{token.Int, "2", 1},
+		{token.Comment, "// half", 1},
+		{token.Comment, "// \"not a string\"", 2},
+		{token.Int, "3", 3},
+		{token.EOF, "", 3},
+	}
+
+	lex := lexer.NewLexer(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		assert.Equal(t, test.expectedType, tok.Type)
+		assert.Equal(t, test.expectedLiteral, tok.Literal)
+		assert.Equal(t, test.expectedLine, tok.Line)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/henningstorck/monkey-interpreter/lint"
+)
+
+// Reports the findings of the linter for the given scripts
+func lintFiles(args []string) {
+	if len(args) == 0 {
+		io.WriteString(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	failed := false
+
+	for _, path := range args {
+		_, program := parseFile(path)
+
+		for _, finding := range lint.Lint(program) {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", path, finding)
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,138 @@
+// Package lint reports code which is valid but almost certainly wrong, like
+// bindings which are never used or conditions which are always true.
+//
+// Findings can be silenced with comments. "// lint:ignore unused-parameter"
+// silences the rule on the line the comment follows code on, or on the next
+// line if the comment is on a line of its own. "// lint:disable shadow"
+// silences it for the rest of the file. Without rule IDs all rules are
+// silenced.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+// The IDs of the rules
+const (
+	UnusedBinding     = "unused-binding"
+	UnusedParameter   = "unused-parameter"
+	Shadow            = "shadow"
+	Unreachable       = "unreachable"
+	SelfComparison    = "self-comparison"
+	ConstantCondition = "constant-condition"
+	Arity             = "arity"
+)
+
+// Finding is a problem reported by a rule.
+type Finding struct {
+	Position token.Position
+	Rule     string
+	Message  string
+}
+
+func (finding Finding) String() string {
+	return finding.Position.String() + ": " + finding.Message + " (" + finding.Rule + ")"
+}
+
+type linter struct {
+	findings     []Finding
+	scopes       []*scope
+	declarations map[*ast.Identifier]bool
+	builtins     map[string]*object.Builtin
+}
+
+// Lint returns the findings for the program in source order, leaving out those
+// silenced by its comments.
+func Lint(program *ast.Program) []Finding {
+	lnt := &linter{declarations: make(map[*ast.Identifier]bool), builtins: make(map[string]*object.Builtin)}
+
+	for _, builtin := range evaluator.Builtins() {
+		lnt.builtins[builtin.Signature.Name] = builtin
+	}
+
+	lnt.push(false)
+	lnt.declareStatements(program)
+	lnt.lint(program)
+	lnt.pop()
+
+	directives := parseDirectives(program.Comments)
+	findings := []Finding{}
+
+	for _, finding := range lnt.findings {
+		if !silenced(directives, finding) {
+			findings = append(findings, finding)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		left, right := findings[i].Position, findings[j].Position
+		return left.Line < right.Line || (left.Line == right.Line && left.Column < right.Column)
+	})
+
+	return findings
+}
+
+func (lnt *linter) report(pos token.Position, rule, format string, args ...any) {
+	lnt.findings = append(lnt.findings, Finding{Position: pos, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// directive is a comment silencing rules. A nil set of rules silences all of
+// them.
+type directive struct {
+	line    int
+	disable bool
+	rules   map[string]bool
+}
+
+func parseDirectives(comments []*ast.Comment) []directive {
+	directives := []directive{}
+
+	for _, comment := range comments {
+		fields := strings.Fields(strings.ReplaceAll(comment.Text(), ",", " "))
+
+		if len(fields) == 0 || (fields[0] != "lint:ignore" && fields[0] != "lint:disable") {
+			continue
+		}
+
+		dir := directive{line: comment.Pos().Line, disable: fields[0] == "lint:disable"}
+
+		if !dir.disable && !comment.Trailing {
+			dir.line++
+		}
+
+		if len(fields) > 1 {
+			dir.rules = make(map[string]bool)
+
+			for _, rule := range fields[1:] {
+				dir.rules[rule] = true
+			}
+		}
+
+		directives = append(directives, dir)
+	}
+
+	return directives
+}
+
+func silenced(directives []directive, finding Finding) bool {
+	for _, dir := range directives {
+		if dir.rules != nil && !dir.rules[finding.Rule] {
+			continue
+		}
+
+		line := finding.Position.Line
+
+		if line == dir.line || (dir.disable && line > dir.line) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/lint"
+	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"fn(x) { let y = 1; x }", []string{"1:13: y is declared but never used (unused-binding)"}},
+		{"fn(x, y) { let [a, _, ...b] = y; a }", []string{"1:4: parameter x is never used (unused-parameter)", "1:26: b is declared but never used (unused-binding)"}},
+		{"fn(x, ...others) { x }", []string{"1:10: parameter others is never used (unused-parameter)"}},
+		{"fn(_x, x, y = x) { y }", nil},
+		{"match (1) { [a, b] => a, _ => 0 }", []string{"1:17: b is declared but never used (unused-binding)"}},
+		{"let x = 1; fn f() { try { 1 } catch (e) { 2 } }", nil},
+		{"fn() { let f = fn() { g() }; let g = fn() { 1 }; f() }", nil},
+		{"fn() { fn even(n) { odd(n) } fn odd(n) { even(n) } even(1) }", nil},
+		{"let x = 1; fn(x) { x }", []string{"1:15: x shadows the binding declared at 1:5 (shadow)"}},
+		{"let f = fn() { let x = 1; fn() { match (x) { x => x } } }", []string{"1:46: x shadows the binding declared at 1:20 (shadow)"}},
+		{"let len = 1; fn(first) { first }", []string{"1:5: len shadows the builtin of the same name (shadow)", "1:17: first shadows the builtin of the same name (shadow)"}},
+		{"let x = 1; let x = x + 1", nil},
+		{"fn(x) { return x; x + 1; 2 }", []string{"1:19: unreachable code (unreachable)"}},
+		{"fn(x) { if (x) { throw \"x\"; 1 } return f(); fn f() { 1 } }", []string{"1:29: unreachable code (unreachable)"}},
+		{"let x = 1; x == x; !x != !x; x + 1 < x + 1", []string{"1:14: x is compared with itself (self-comparison)", "1:23: (!x) is compared with itself (self-comparison)", "1:36: (x + 1) is compared with itself (self-comparison)"}},
+		{"let x = 1; x == 1; [1] == [1]; rest([]) == rest([]); x.a == x.a; x[1] == x[1]; -x == -x; \"a\" == \"a\"; x + x", nil},
+		{"if (true) { 1 }; if (!0) { 2 }; if (\"\") { 3 }", []string{"1:1: condition is always true (constant-condition)", "1:18: condition is always false (constant-condition)", "1:33: condition is always true (constant-condition)"}},
+		{"if (1 < 2) { 1 }; if (!(2 > 1)) { 2 }; if (\"a\" + \"b\") { 3 }", []string{"1:1: condition is always true (constant-condition)", "1:19: condition is always false (constant-condition)", "1:40: condition is always true (constant-condition)"}},
+		{"if ([]) { 1 }; if ({}) { 2 }; if (fn() {}) { 3 }", []string{"1:1: condition is always true (constant-condition)", "1:16: condition is always true (constant-condition)", "1:31: condition is always true (constant-condition)"}},
+		{"let x = true; if (x) { 1 }; if (x == 1) { 2 }; if (1 / 0) { 3 }", nil},
+		{"fn f(a, b = 1) { a + b } f(); f(1, 2, 3); f(1, b: 2); f(1, b: 2, c: 3)", []string{"1:26: f: wrong number of arguments. got 0, but expected 1 to 2 (arity)", "1:31: f: wrong number of arguments. got 3, but expected 1 to 2 (arity)", "1:55: f: wrong number of arguments. got 3, but expected 1 to 2 (arity)"}},
+		{"let f = fn(a, ...b) { [a, b] }; f(); f(1, 2, 3)", []string{"1:33: f: wrong number of arguments. got 0, but expected at least 1 (arity)"}},
+		{"len(); len([1], 2); len(value: [1])", []string{"1:1: len: wrong number of arguments. got 0, but expected 1 (arity)", "1:8: len: wrong number of arguments. got 2, but expected 1 (arity)"}},
+		{"let f = fn(a) { a }; f(...[1, 2]); let g = fn() { 1 }; let g = fn(a) { a }; g(1, 2)", nil},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, findings(t, test.input), test.input)
+	}
+}
+
+func TestLintDirectives(t *testing.T) {
+	input := `fn(x) {
+	// lint:ignore unused-parameter
+	fn(y) { 1 };
+	fn(z) { 2 }; // lint:ignore
+	fn(w) { true == true }; // lint:ignore self-comparison
+	// lint:disable unused-parameter, unreachable
+	fn(v) { return 1; 2 };
+	if (true) { 3 }
+}`
+
+	assert.Equal(t, []string{
+		"1:4: parameter x is never used (unused-parameter)",
+		"5:5: parameter w is never used (unused-parameter)",
+		"8:2: condition is always true (constant-condition)",
+	}, findings(t, input))
+}
+
+func findings(t *testing.T, input string) []string {
+	program := testParse(t, input)
+	var messages []string
+
+	for _, finding := range lint.Lint(program) {
+		messages = append(messages, finding.String())
+	}
+
+	return messages
+}
+
+func testParse(t *testing.T, input string) *ast.Program {
+	lex := lexer.NewLexer(input)
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+	assert.Empty(t, par.Errors(), input)
+	return program
+}
@@ -0,0 +1,110 @@
+package lint
+
+import (
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/optimizer"
+)
+
+// Function declarations are hoisted, so they are never unreachable
+func (lnt *linter) checkUnreachable(stmts []ast.Statement) {
+	returned := false
+
+	for _, stmt := range stmts {
+		switch stmt.(type) {
+		case *ast.ReturnStatement, *ast.ThrowStatement:
+			if !returned {
+				returned = true
+				continue
+			}
+		case *ast.FunctionDeclaration:
+			continue
+		}
+
+		if returned {
+			lnt.report(stmt.Pos(), Unreachable, "unreachable code")
+			return
+		}
+	}
+}
+
+// The result is not reported, as comparisons of some types fail instead
+func (lnt *linter) checkSelfComparison(infixExp *ast.InfixExpression) {
+	switch infixExp.Operator {
+	case "==", "!=", "<", ">":
+	default:
+		return
+	}
+
+	if pure(infixExp.Left) && pure(infixExp.Right) && infixExp.Left.String() == infixExp.Right.String() {
+		lnt.report(infixExp.Pos(), SelfComparison, "%s is compared with itself", infixExp.Left)
+	}
+}
+
+// Reports whether evaluating the expression twice gives the same value. Only
+// the ! operator is accepted as a prefix, as it never fails. Strings cannot be
+// compared, while array and hash literals create a new value every time.
+func pure(exp ast.Expression) bool {
+	switch exp := exp.(type) {
+	case *ast.Identifier, *ast.IntegerLiteral, *ast.BooleanLiteral:
+		return true
+	case *ast.PrefixExpression:
+		return exp.Operator == "!" && pure(exp.Right)
+	case *ast.InfixExpression:
+		return pure(exp.Left) && pure(exp.Right)
+	default:
+		return false
+	}
+}
+
+func (lnt *linter) checkCondition(ifExp *ast.IfExpression) {
+	if truthy, ok := constantCondition(ifExp.Condition); ok {
+		lnt.report(ifExp.Pos(), ConstantCondition, "condition is always %t", truthy)
+	}
+}
+
+// Array, hash and function literals are always truthy, even though the
+// optimizer does not fold them
+func constantCondition(exp ast.Expression) (bool, bool) {
+	switch exp.(type) {
+	case *ast.ArrayLiteral, *ast.HashLiteral, *ast.FunctionLiteral:
+		return true, true
+	default:
+		return optimizer.Truthiness(exp)
+	}
+}
+
+// Calls are checked if they pass no arrays with spread. Keyword arguments are
+// counted like the others.
+func (lnt *linter) checkArity(call *ast.CallExpression) {
+	ident, ok := call.Function.(*ast.Identifier)
+
+	if !ok {
+		return
+	}
+
+	for _, arg := range call.Arguments {
+		if _, ok := arg.(*ast.SpreadExpression); ok {
+			return
+		}
+	}
+
+	count := len(call.Arguments) + len(call.Keywords)
+	var min, max int
+
+	if b := lnt.lookup(ident.Value); b != nil {
+		if b.count > 1 || b.fn == nil {
+			return
+		}
+
+		min, max = b.fn.Arity()
+	} else if builtin, ok := lnt.builtins[ident.Value]; ok && len(call.Keywords) == 0 {
+		min, max = builtin.Signature.Arity()
+	} else {
+		return
+	}
+
+	if msg := object.ArityError(count, min, max); msg != "" {
+		lnt.report(ident.Pos(), Arity, "%s: %s", ident.Value, msg)
+	}
+}
@@ -0,0 +1,222 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+)
+
+type kind int
+
+const (
+	letBinding kind = iota
+	parameter
+	function
+	catchParameter
+)
+
+// binding is a name declared in a scope. A name declared once with a function
+// literal is a known function, whose calls can be checked.
+type binding struct {
+	ident *ast.Identifier
+	kind  kind
+	count int
+	fn    *ast.FunctionLiteral
+	used  bool
+}
+
+// Only local scopes report their unused bindings. Bindings of the program may
+// be used by importers, tests or the REPL.
+type scope struct {
+	names    []string
+	bindings map[string]*binding
+	local    bool
+}
+
+func (lnt *linter) push(local bool) *scope {
+	sc := &scope{bindings: make(map[string]*binding), local: local}
+	lnt.scopes = append(lnt.scopes, sc)
+	return sc
+}
+
+func (lnt *linter) pop() {
+	sc := lnt.scopes[len(lnt.scopes)-1]
+	lnt.scopes = lnt.scopes[:len(lnt.scopes)-1]
+
+	if !sc.local {
+		return
+	}
+
+	for _, name := range sc.names {
+		b := sc.bindings[name]
+
+		if b.used || strings.HasPrefix(name, "_") {
+			continue
+		}
+
+		switch b.kind {
+		case letBinding:
+			lnt.report(b.ident.Pos(), UnusedBinding, "%s is declared but never used", name)
+		case parameter:
+			lnt.report(b.ident.Pos(), UnusedParameter, "parameter %s is never used", name)
+		}
+	}
+}
+
+// Declares a name in the innermost scope. Names declared by the scope before
+// are not shadowed, they are bound again.
+func (lnt *linter) declare(ident *ast.Identifier, kind kind, fn *ast.FunctionLiteral) {
+	sc := lnt.scopes[len(lnt.scopes)-1]
+	lnt.declarations[ident] = true
+
+	if b, ok := sc.bindings[ident.Value]; ok {
+		b.count++
+		b.fn = nil
+		return
+	}
+
+	sc.names = append(sc.names, ident.Value)
+	sc.bindings[ident.Value] = &binding{ident: ident, kind: kind, count: 1, fn: fn}
+
+	if strings.HasPrefix(ident.Value, "_") {
+		return
+	}
+
+	if outer := lnt.lookupFrom(len(lnt.scopes)-2, ident.Value); outer != nil {
+		lnt.report(ident.Pos(), Shadow, "%s shadows the binding declared at %s", ident.Value, outer.ident.Pos())
+	} else if _, ok := lnt.builtins[ident.Value]; ok {
+		lnt.report(ident.Pos(), Shadow, "%s shadows the builtin of the same name", ident.Value)
+	}
+}
+
+func (lnt *linter) lookup(name string) *binding {
+	return lnt.lookupFrom(len(lnt.scopes)-1, name)
+}
+
+func (lnt *linter) lookupFrom(index int, name string) *binding {
+	for i := index; i >= 0; i-- {
+		if b, ok := lnt.scopes[i].bindings[name]; ok {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// Declares the names bound by the node before it is linted, so closures may
+// refer to bindings declared after them
+func (lnt *linter) declareStatements(node ast.Node) {
+	ast.WalkDeclarations(node, func(ident *ast.Identifier, stmt ast.Statement) {
+		switch stmt := stmt.(type) {
+		case *ast.LetStatement:
+			fnLiteral, _ := stmt.Value.(*ast.FunctionLiteral)
+
+			if stmt.Pattern != nil {
+				fnLiteral = nil
+			}
+
+			lnt.declare(ident, letBinding, fnLiteral)
+		case *ast.FunctionDeclaration:
+			lnt.declare(ident, function, stmt.Function)
+		}
+	})
+}
+
+func (lnt *linter) lint(node ast.Node) {
+	ast.Walk(node, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Identifier:
+			if b := lnt.lookup(node.Value); b != nil && !lnt.declarations[node] {
+				b.used = true
+			}
+		case *ast.Program:
+			lnt.checkUnreachable(node.Statements)
+		case *ast.BlockStatement:
+			lnt.checkUnreachable(node.Statements)
+		case *ast.InfixExpression:
+			lnt.checkSelfComparison(node)
+		case *ast.IfExpression:
+			lnt.checkCondition(node)
+		case *ast.CallExpression:
+			lnt.checkArity(node)
+		case *ast.FunctionLiteral:
+			lnt.lintFunction(node)
+			return false
+		case *ast.MatchExpression:
+			lnt.lint(node.Subject)
+
+			for _, arm := range node.Arms {
+				lnt.lintArm(arm)
+			}
+
+			return false
+		case *ast.TryExpression:
+			lnt.lint(node.Block)
+
+			if node.Catch != nil {
+				lnt.lintCatch(node)
+			}
+
+			if node.Finally != nil {
+				lnt.lint(node.Finally)
+			}
+
+			return false
+		}
+
+		return true
+	})
+}
+
+func (lnt *linter) lintFunction(fnLiteral *ast.FunctionLiteral) {
+	lnt.push(true)
+
+	for _, param := range fnLiteral.Parameters {
+		lnt.declare(param, parameter, nil)
+	}
+
+	if fnLiteral.Rest != nil {
+		lnt.declare(fnLiteral.Rest, parameter, nil)
+	}
+
+	lnt.declareStatements(fnLiteral.Body)
+
+	for i := range fnLiteral.Parameters {
+		if i < len(fnLiteral.Defaults) && fnLiteral.Defaults[i] != nil {
+			lnt.lint(fnLiteral.Defaults[i])
+		}
+	}
+
+	lnt.lint(fnLiteral.Body)
+	lnt.pop()
+}
+
+func (lnt *linter) lintArm(arm *ast.MatchArm) {
+	lnt.push(true)
+
+	for _, ident := range ast.PatternIdentifiers(arm.Pattern) {
+		lnt.declare(ident, letBinding, nil)
+	}
+
+	if arm.Guard != nil {
+		lnt.declareStatements(arm.Guard)
+	}
+
+	lnt.declareStatements(arm.Body)
+	lnt.lint(arm.Pattern)
+
+	if arm.Guard != nil {
+		lnt.lint(arm.Guard)
+	}
+
+	lnt.lint(arm.Body)
+	lnt.pop()
+}
+
+func (lnt *linter) lintCatch(tryExp *ast.TryExpression) {
+	lnt.push(true)
+	lnt.declare(tryExp.Parameter, catchParameter, nil)
+	lnt.declareStatements(tryExp.Catch)
+	lnt.lint(tryExp.Catch)
+	lnt.pop()
+}
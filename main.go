@@ -2,13 +2,86 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/user"
+	"path/filepath"
 
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/parser"
 	"github.com/henningstorck/monkey-interpreter/repl"
 )
 
+const usage = `Usage:
+	monkey                     start the interactive REPL
+	monkey run <file>          run a script
+	monkey bench <file>        run a script repeatedly and report its performance
+	monkey test [paths]        run the tests in the *_test.monkey files in the given directories
+	monkey check <files>       report type errors without running the scripts
+	monkey lint <files>        report code that is likely to be wrong
+	monkey debug <file>        debug a script interactively
+	monkey debug --dap         serve the Debug Adapter Protocol on stdin and stdout
+
+Imports are resolved against the directory of the importing file, followed by
+the directories given with "run --path" and the MONKEYPATH environment variable.
+
+Scripts are optimized before they run. "run --dump-ast" prints the optimized
+program instead and "run --optimize=false" turns the optimizer off. Benchmarks
+run a script 10 times, which "bench -n" changes. "run --profile out.pprof"
+writes a profile for "go tool pprof" and prints the functions and lines the
+most time was spent in, as many as "--profile-top" says. "run --trace" writes
+each evaluated node, function call and let binding to stderr.
+
+Tests are the functions named test_* in test files, which can use the builtins
+assert, assert_eq and assert_error. "test --format" reports them as text, tap or
+junit. "test --cover" reports the statements and if branches the tests evaluated per
+file, "--cover-list" prints the files annotated with how often each line was
+evaluated and "--cover-lcov out.info" writes the coverage in the LCOV format.
+
+Bindings and parameters may be annotated with the types int, string, bool,
+array, hash, fn, null, module and exception, e.g. "let x: int = 5" or
+"fn(a: int): bool { ... }". "check" reports the type errors it can find without
+running a script. Anything it cannot infer is accepted.
+
+"lint" reports unused bindings and parameters, shadowed names and builtins,
+unreachable code, self-comparisons, constant if conditions and calls with the
+wrong number of arguments. A comment "// lint:ignore <rules>" silences rules
+for its line, "// lint:disable <rules>" for the rest of the file.
+`
+
 func main() {
+	evaluator.SearchPath = filepath.SplitList(os.Getenv("MONKEYPATH"))
+
+	if len(os.Args) < 2 {
+		startRepl()
+		return
+	}
+
+	switch os.Args[1] {
+	case "run":
+		run(os.Args[2:])
+	case "bench":
+		bench(os.Args[2:])
+	case "test":
+		test(os.Args[2:])
+	case "check":
+		check(os.Args[2:])
+	case "lint":
+		lintFiles(os.Args[2:])
+	case "debug":
+		debug(os.Args[2:])
+	case "help", "-h", "--help":
+		io.WriteString(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n", os.Args[1])
+		io.WriteString(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func startRepl() {
 	user, err := user.Current()
 
 	if err != nil {
@@ -18,3 +91,32 @@ func main() {
 	fmt.Printf("Hey %s! This is the Monkey programming language.\n", user.Username)
 	repl.Start(os.Stdin, os.Stdout)
 }
+
+func parseFile(path string) (string, *ast.Program) {
+	source, err := os.ReadFile(path)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	lex := lexer.NewLexer(string(source))
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+
+	if len(par.Errors()) != 0 {
+		fmt.Fprintf(os.Stderr, "%s: parser errors:\n", path)
+
+		for _, msg := range par.Errors() {
+			fmt.Fprintf(os.Stderr, "\t%s\n", msg)
+		}
+
+		os.Exit(1)
+	}
+
+	for _, msg := range par.Warnings() {
+		fmt.Fprintf(os.Stderr, "%s: warning: %s\n", path, msg)
+	}
+
+	return string(source), program
+}
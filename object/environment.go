@@ -1,8 +1,16 @@
 package object
 
+import "sort"
+
+// Environment binds names to values. Global environments store their bindings
+// in a map, while frames created for resolved scopes store them in slots, with
+// names listing the name of each slot.
 type Environment struct {
 	store map[string]Object
+	names []string
+	slots []Object
 	outer *Environment
+	file  string
 }
 
 func NewEnvironment() *Environment {
@@ -16,7 +24,19 @@ func NewEnclosedEnvironment(outer *Environment) *Environment {
 	return env
 }
 
+// NewFrame creates an environment with a slot for each of the names, which are
+// shared by all frames of the same scope.
+func NewFrame(outer *Environment, names []string) *Environment {
+	return &Environment{names: names, slots: make([]Object, len(names)), outer: outer}
+}
+
 func (env *Environment) Get(name string) (Object, bool) {
+	for i, slotName := range env.names {
+		if slotName == name && env.slots[i] != nil {
+			return env.slots[i], true
+		}
+	}
+
 	value, ok := env.store[name]
 
 	if !ok && env.outer != nil {
@@ -27,6 +47,66 @@ func (env *Environment) Get(name string) (Object, bool) {
 }
 
 func (env *Environment) Set(name string, value Object) Object {
+	for i, slotName := range env.names {
+		if slotName == name {
+			env.slots[i] = value
+			return value
+		}
+	}
+
+	if env.store == nil {
+		env.store = make(map[string]Object)
+	}
+
 	env.store[name] = value
 	return value
 }
+
+// GetSlot returns the value of a slot of the frame depth levels up. Slots are
+// empty until their binding has been evaluated.
+func (env *Environment) GetSlot(depth, slot int) (Object, bool) {
+	for ; depth > 0; depth-- {
+		env = env.outer
+	}
+
+	value := env.slots[slot]
+	return value, value != nil
+}
+
+func (env *Environment) SetSlot(slot int, value Object) Object {
+	env.slots[slot] = value
+	return value
+}
+
+func (env *Environment) Outer() *Environment {
+	return env.outer
+}
+
+// File returns the path of the file whose program was evaluated in the
+// environment, if it was set.
+func (env *Environment) File() string {
+	return env.file
+}
+
+func (env *Environment) SetFile(path string) {
+	env.file = path
+}
+
+// Names returns the sorted names of all bindings stored directly in this
+// environment, ignoring outer environments.
+func (env *Environment) Names() []string {
+	names := make([]string, 0, len(env.store)+len(env.names))
+
+	for i, name := range env.names {
+		if env.slots[i] != nil {
+			names = append(names, name)
+		}
+	}
+
+	for name := range env.store {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
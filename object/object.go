@@ -3,9 +3,11 @@ package object
 import (
 	"bytes"
 	"fmt"
+	"hash/fnv"
 	"strings"
 
 	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/token"
 )
 
 const (
@@ -18,6 +20,9 @@ const (
 	StringObj      = "STRING"
 	BuiltinObj     = "BUILTIN"
 	ArrayObj       = "ARRAY"
+	ExceptionObj   = "EXCEPTION"
+	ModuleObj      = "MODULE"
+	HashObj        = "HASH"
 )
 
 type ObjectType string
@@ -34,6 +39,10 @@ type Integer struct {
 func (integer *Integer) Inspect() string  { return fmt.Sprintf("%d", integer.Value) }
 func (integer *Integer) Type() ObjectType { return IntegerObj }
 
+func (integer *Integer) HashKey() HashKey {
+	return HashKey{Type: integer.Type(), Value: uint64(integer.Value)}
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -41,6 +50,16 @@ type Boolean struct {
 func (boolean *Boolean) Inspect() string  { return fmt.Sprintf("%t", boolean.Value) }
 func (boolean *Boolean) Type() ObjectType { return BooleanObj }
 
+func (boolean *Boolean) HashKey() HashKey {
+	var value uint64
+
+	if boolean.Value {
+		value = 1
+	}
+
+	return HashKey{Type: boolean.Type(), Value: value}
+}
+
 type Null struct{}
 
 func (null *Null) Inspect() string  { return "null" }
@@ -54,15 +73,73 @@ func (returnValue *ReturnValue) Type() ObjectType { return ReturnValueObj }
 func (returnValue *ReturnValue) Inspect() string  { return returnValue.Value.Inspect() }
 
 type Error struct {
-	Message string
+	Kind     string
+	Message  string
+	Value    Object
+	Position token.Position
+	Stack    []StackFrame
+}
+
+// StackFrame is a function call an error propagated out of, starting with the
+// innermost call.
+type StackFrame struct {
+	Function string
+	Position token.Position
 }
 
 func (err *Error) Type() ObjectType { return ErrorObj }
 func (err *Error) Inspect() string  { return "ERROR: " + err.Message }
 
+// Trace resolves the call chain to the position within each function,
+// starting with the innermost one.
+func (err *Error) Trace() []StackFrame {
+	frames := []StackFrame{}
+	position := err.Position
+
+	for _, frame := range err.Stack {
+		frames = append(frames, StackFrame{Function: frame.Function, Position: position})
+		position = frame.Position
+	}
+
+	if position != (token.Position{}) {
+		frames = append(frames, StackFrame{Function: "<main>", Position: position})
+	}
+
+	return frames
+}
+
+// Traceback renders the message followed by the call chain the error
+// propagated through.
+func (err *Error) Traceback() string {
+	var out bytes.Buffer
+	out.WriteString(err.Inspect())
+
+	for _, frame := range err.Trace() {
+		out.WriteString(fmt.Sprintf("\n\tat %s (%s)", frame.Function, frame.Position))
+	}
+
+	return out.String()
+}
+
+// Exception is an error which has been caught, so it is an ordinary value
+// instead of aborting the evaluation.
+type Exception struct {
+	Error *Error
+}
+
+func (exception *Exception) Type() ObjectType { return ExceptionObj }
+
+func (exception *Exception) Inspect() string {
+	return exception.Error.Kind + ": " + exception.Error.Message
+}
+
 type Function struct {
+	Name       string
 	Parameters []*ast.Identifier
+	Defaults   []ast.Expression
+	Rest       *ast.Identifier
 	Body       *ast.BlockStatement
+	Locals     []string
 	Env        *Environment
 }
 
@@ -70,15 +147,14 @@ func (fn *Function) Type() ObjectType { return FunctionObj }
 
 func (fn *Function) Inspect() string {
 	var out bytes.Buffer
-	params := []string{}
+	out.WriteString("fn")
 
-	for _, param := range fn.Parameters {
-		params = append(params, param.String())
+	if fn.Name != "" {
+		out.WriteString(" " + fn.Name)
 	}
 
-	out.WriteString("fn")
 	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(ast.ParametersString(fn.Parameters, nil, fn.Defaults, fn.Rest))
 	out.WriteString(") {\n")
 	out.WriteString(fn.Body.String())
 	out.WriteString("\n}")
@@ -92,14 +168,21 @@ type String struct {
 func (str *String) Type() ObjectType { return StringObj }
 func (str *String) Inspect() string  { return str.Value }
 
+func (str *String) HashKey() HashKey {
+	hash := fnv.New64a()
+	hash.Write([]byte(str.Value))
+	return HashKey{Type: str.Type(), Value: hash.Sum64()}
+}
+
 type BuiltinFunction func(args ...Object) Object
 
 type Builtin struct {
-	Function BuiltinFunction
+	Signature Signature
+	Function  BuiltinFunction
 }
 
 func (builtin *Builtin) Type() ObjectType { return BuiltinObj }
-func (builtin *Builtin) Inspect() string  { return "builtin function" }
+func (builtin *Builtin) Inspect() string  { return "builtin " + builtin.Signature.String() }
 
 type Array struct {
 	Elements []Object
@@ -120,3 +203,68 @@ func (arr *Array) Inspect() string {
 	out.WriteString("]")
 	return out.String()
 }
+
+type Module struct {
+	Path    string
+	Exports map[string]Object
+}
+
+func (module *Module) Type() ObjectType { return ModuleObj }
+func (module *Module) Inspect() string  { return "module " + module.Path }
+
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+}
+
+// Hashable is implemented by all objects usable as hash keys.
+type Hashable interface {
+	HashKey() HashKey
+}
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// Hash keeps the keys in insertion order, so it is inspected in a stable way.
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	Keys  []HashKey
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+func (hash *Hash) Type() ObjectType { return HashObj }
+
+func (hash *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+
+	if _, ok := hash.Pairs[hashKey]; !ok {
+		hash.Keys = append(hash.Keys, hashKey)
+	}
+
+	hash.Pairs[hashKey] = HashPair{Key: key.(Object), Value: value}
+}
+
+func (hash *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := hash.Pairs[key.HashKey()]
+	return pair.Value, ok
+}
+
+func (hash *Hash) Inspect() string {
+	var out bytes.Buffer
+	pairs := []string{}
+
+	for _, key := range hash.Keys {
+		pair := hash.Pairs[key]
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+	return out.String()
+}
@@ -0,0 +1,134 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnyObj is used in signatures for parameters and results of any type.
+const AnyObj = "ANY"
+
+type Parameter struct {
+	Name     string
+	Types    []ObjectType
+	Optional bool
+	Variadic bool
+}
+
+// Accepts reports whether an argument of the given type may be passed for the
+// parameter. A parameter without types accepts anything.
+func (param Parameter) Accepts(objType ObjectType) bool {
+	if len(param.Types) == 0 {
+		return true
+	}
+
+	for _, paramType := range param.Types {
+		if paramType == objType || paramType == AnyObj {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (param Parameter) String() string {
+	var out strings.Builder
+
+	if param.Variadic {
+		out.WriteString("...")
+	}
+
+	out.WriteString(param.Name)
+
+	if param.Optional {
+		out.WriteString("?")
+	}
+
+	out.WriteString(": ")
+	out.WriteString(typeList(param.Types))
+	return out.String()
+}
+
+type Signature struct {
+	Name       string
+	Doc        string
+	Parameters []Parameter
+	Returns    ObjectType
+}
+
+// Arity returns the minimum and maximum number of arguments. The maximum is
+// -1 for variadic signatures.
+func (sig *Signature) Arity() (int, int) {
+	min := 0
+
+	for _, param := range sig.Parameters {
+		if param.Variadic {
+			return min, -1
+		}
+
+		if !param.Optional {
+			min++
+		}
+	}
+
+	return min, len(sig.Parameters)
+}
+
+// ArityError returns the error message for calls passing count arguments to a
+// function taking min to max, or an empty string if the count is fine.
+func ArityError(count, min, max int) string {
+	switch {
+	case max < 0 && count < min:
+		return fmt.Sprintf("wrong number of arguments. got %d, but expected at least %d", count, min)
+	case min == max && count != min:
+		return fmt.Sprintf("wrong number of arguments. got %d, but expected %d", count, min)
+	case max >= 0 && (count < min || count > max):
+		return fmt.Sprintf("wrong number of arguments. got %d, but expected %d to %d", count, min, max)
+	}
+
+	return ""
+}
+
+// Parameter returns the parameter the argument at the given position is
+// passed for.
+func (sig *Signature) Parameter(index int) (Parameter, bool) {
+	if index < len(sig.Parameters) {
+		return sig.Parameters[index], true
+	}
+
+	if len(sig.Parameters) > 0 && sig.Parameters[len(sig.Parameters)-1].Variadic {
+		return sig.Parameters[len(sig.Parameters)-1], true
+	}
+
+	return Parameter{}, false
+}
+
+func (sig *Signature) String() string {
+	params := []string{}
+
+	for _, param := range sig.Parameters {
+		params = append(params, param.String())
+	}
+
+	returns := sig.Returns
+
+	if returns == "" {
+		returns = AnyObj
+	}
+
+	return sig.Name + "(" + strings.Join(params, ", ") + "): " + string(returns)
+}
+
+func typeList(types []ObjectType) string {
+	if len(types) == 0 {
+		return AnyObj
+	}
+
+	names := make([]string, len(types))
+
+	for i, objType := range types {
+		names[i] = string(objType)
+	}
+
+	return strings.Join(names, " | ")
+}
@@ -0,0 +1,235 @@
+package optimizer
+
+import (
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+// Functions whose body consists of more nodes are not inlined
+const maxInlineSize = 16
+
+// A top level function that is bound exactly once and returns a pure
+// expression of its parameters. Such a function never calls anything, so it
+// cannot be recursive.
+type inlineCandidate struct {
+	definition ast.Statement
+	hoisted    bool
+	parameters []*ast.Identifier
+	body       ast.Expression
+}
+
+func findInlineCandidates(program *ast.Program) map[string]*inlineCandidate {
+	bindings := countBindings(program)
+	candidates := make(map[string]*inlineCandidate)
+
+	for _, stmt := range program.Statements {
+		var name string
+		var fnLiteral *ast.FunctionLiteral
+		hoisted := false
+
+		if exportStmt, ok := stmt.(*ast.ExportStatement); ok {
+			stmt = exportStmt.Statement
+		}
+
+		switch stmt := stmt.(type) {
+		case *ast.LetStatement:
+			fnLiteral, _ = stmt.Value.(*ast.FunctionLiteral)
+
+			if stmt.Pattern == nil {
+				name = stmt.Name.Value
+			}
+		case *ast.FunctionDeclaration:
+			name, fnLiteral, hoisted = stmt.Function.Name.Value, stmt.Function, true
+		}
+
+		if fnLiteral == nil || bindings[name] != 1 {
+			continue
+		}
+
+		if body, ok := inlineBody(fnLiteral); ok {
+			candidates[name] = &inlineCandidate{
+				definition: stmt,
+				hoisted:    hoisted,
+				parameters: fnLiteral.Parameters,
+				body:       body,
+			}
+		}
+	}
+
+	return candidates
+}
+
+// Counts how often each name is bound anywhere in the program, as a call can
+// only be inlined if its name always refers to the same function
+func countBindings(program *ast.Program) map[string]int {
+	bindings := make(map[string]int)
+
+	ast.Walk(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.LetStatement:
+			for _, name := range node.Names() {
+				bindings[name]++
+			}
+		case *ast.FunctionLiteral:
+			if node.Name != nil {
+				bindings[node.Name.Value]++
+			}
+
+			for _, param := range node.Parameters {
+				bindings[param.Value]++
+			}
+
+			if node.Rest != nil {
+				bindings[node.Rest.Value]++
+			}
+		case *ast.MatchExpression:
+			for _, arm := range node.Arms {
+				for _, name := range ast.PatternNames(arm.Pattern) {
+					bindings[name]++
+				}
+			}
+		case *ast.TryExpression:
+			if node.Catch != nil {
+				bindings[node.Parameter.Value]++
+			}
+		}
+
+		return true
+	})
+
+	return bindings
+}
+
+// Returns the expression a function consists of if it is small enough and only
+// uses operators, literals and its parameters. Each parameter has to be used,
+// in the order of the parameters, so the arguments are evaluated in the same
+// order after inlining.
+func inlineBody(fnLiteral *ast.FunctionLiteral) (ast.Expression, bool) {
+	if fnLiteral.Rest != nil || len(fnLiteral.Body.Statements) != 1 {
+		return nil, false
+	}
+
+	for _, defaultValue := range fnLiteral.Defaults {
+		if defaultValue != nil {
+			return nil, false
+		}
+	}
+
+	exprStmt, ok := fnLiteral.Body.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		return nil, false
+	}
+
+	params := make(map[string]int)
+
+	for i, param := range fnLiteral.Parameters {
+		params[param.Value] = i
+	}
+
+	size, used, pure := 0, 0, true
+
+	ast.Walk(exprStmt.Expression, func(node ast.Node) bool {
+		size++
+
+		switch node := node.(type) {
+		case *ast.Identifier:
+			index, ok := params[node.Value]
+
+			if !ok || index > used {
+				pure = false
+			} else if index == used {
+				used++
+			}
+		case *ast.IntegerLiteral, *ast.StringLiteral, *ast.BooleanLiteral, *ast.PrefixExpression, *ast.InfixExpression:
+		default:
+			pure = false
+		}
+
+		return pure
+	})
+
+	if !pure || size > maxInlineSize || used != len(fnLiteral.Parameters) {
+		return nil, false
+	}
+
+	return exprStmt.Expression, true
+}
+
+// Replaces a call to an inline candidate with the body of the function. Only
+// literals and identifiers are passed as arguments, since they are evaluated
+// once before the call, while the parameters might be used several times.
+func (opt *optimizer) inline(callExp *ast.CallExpression) ast.Expression {
+	ident, ok := callExp.Function.(*ast.Identifier)
+
+	if !ok || len(callExp.Keywords) > 0 {
+		return nil
+	}
+
+	candidate, ok := opt.inlinable[ident.Value]
+
+	if !ok || len(callExp.Arguments) != len(candidate.parameters) || !opt.bound(candidate) {
+		return nil
+	}
+
+	args := make(map[string]ast.Expression)
+
+	for i, arg := range callExp.Arguments {
+		switch arg.(type) {
+		case *ast.Identifier, *ast.IntegerLiteral, *ast.StringLiteral, *ast.BooleanLiteral:
+			args[candidate.parameters[i].Value] = arg
+		default:
+			return nil
+		}
+	}
+
+	return substitute(candidate.body, args, callExp.Token.Position)
+}
+
+// Reports whether the candidate is bound when the current statement runs. Let
+// statements bind their function once they are evaluated, while code hoisted
+// to the start of the program may run before that.
+func (opt *optimizer) bound(candidate *inlineCandidate) bool {
+	if candidate.hoisted {
+		return true
+	}
+
+	if _, ok := opt.statement.(*ast.FunctionDeclaration); ok {
+		return false
+	}
+
+	return before(candidate.definition.Pos(), opt.statement.Pos())
+}
+
+func before(left, right token.Position) bool {
+	return left.Line < right.Line || (left.Line == right.Line && left.Column < right.Column)
+}
+
+// Copies the expression with the parameters replaced by the arguments. The
+// copies are positioned at the call, so errors point to it.
+func substitute(exp ast.Expression, args map[string]ast.Expression, position token.Position) ast.Expression {
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		arg := args[exp.Value]
+
+		if ident, ok := arg.(*ast.Identifier); ok {
+			clone := *ident
+			return &clone
+		}
+
+		return arg
+	case *ast.PrefixExpression:
+		clone := *exp
+		clone.Token.Position = position
+		clone.Right = substitute(exp.Right, args, position)
+		return &clone
+	case *ast.InfixExpression:
+		clone := *exp
+		clone.Token.Position = position
+		clone.Left = substitute(exp.Left, args, position)
+		clone.Right = substitute(exp.Right, args, position)
+		return &clone
+	default:
+		return exp
+	}
+}
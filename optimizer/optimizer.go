@@ -0,0 +1,428 @@
+// Package optimizer rewrites a parsed program into an equivalent one that is
+// cheaper to evaluate. It folds constant expressions, removes branches that can
+// never be taken and inlines calls to small functions.
+//
+// The optimizer runs before the resolver, as inlining moves expressions into
+// other scopes.
+package optimizer
+
+import (
+	"strconv"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+type optimizer struct {
+	inlinable map[string]*inlineCandidate
+	// The top level statement being optimized, which decides whether an
+	// inline candidate is already bound
+	statement ast.Statement
+}
+
+// Optimize rewrites the program in place and returns it.
+func Optimize(program *ast.Program) *ast.Program {
+	opt := &optimizer{inlinable: findInlineCandidates(program)}
+	statements := []ast.Statement{}
+
+	for i, stmt := range program.Statements {
+		opt.statement = stmt
+		statements = opt.appendStatement(statements, stmt, i == len(program.Statements)-1)
+	}
+
+	program.Statements = statements
+	return program
+}
+
+func (opt *optimizer) block(block *ast.BlockStatement) *ast.BlockStatement {
+	if block == nil {
+		return nil
+	}
+
+	statements := []ast.Statement{}
+
+	for i, stmt := range block.Statements {
+		statements = opt.appendStatement(statements, stmt, i == len(block.Statements)-1)
+	}
+
+	block.Statements = statements
+	return block
+}
+
+// Appends the optimized statement, replacing if expressions with a constant
+// condition by the statements of the branch taken. Branches declaring
+// functions are kept, as their declarations are hoisted to the start of the
+// block. A missing branch evaluates to null, so the statement is only kept if
+// it provides the value of the block.
+func (opt *optimizer) appendStatement(stmts []ast.Statement, stmt ast.Statement, last bool) []ast.Statement {
+	stmt = opt.statementNode(stmt)
+	exprStmt, ok := stmt.(*ast.ExpressionStatement)
+
+	if !ok {
+		return append(stmts, stmt)
+	}
+
+	ifExp, ok := exprStmt.Expression.(*ast.IfExpression)
+
+	if !ok {
+		return append(stmts, stmt)
+	}
+
+	condition, ok := ifExp.Condition.(*ast.BooleanLiteral)
+
+	if !ok {
+		return append(stmts, stmt)
+	}
+
+	branch := ifExp.Alternative
+
+	if condition.Value {
+		branch = ifExp.Consequence
+	}
+
+	switch {
+	case branch == nil && !last:
+		return stmts
+	case branch == nil || len(branch.Statements) == 0 || declaresFunctions(branch):
+		return append(stmts, stmt)
+	default:
+		return append(stmts, branch.Statements...)
+	}
+}
+
+func declaresFunctions(block *ast.BlockStatement) bool {
+	for _, stmt := range block.Statements {
+		if _, ok := stmt.(*ast.FunctionDeclaration); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (opt *optimizer) statementNode(stmt ast.Statement) ast.Statement {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		stmt.Value = opt.expression(stmt.Value)
+	case *ast.ExportStatement:
+		opt.statementNode(stmt.Statement)
+	case *ast.FunctionDeclaration:
+		opt.function(stmt.Function)
+	case *ast.ReturnStatement:
+		stmt.ReturnValue = opt.expression(stmt.ReturnValue)
+	case *ast.ThrowStatement:
+		stmt.Value = opt.expression(stmt.Value)
+	case *ast.ExpressionStatement:
+		stmt.Expression = opt.expression(stmt.Expression)
+	case *ast.BlockStatement:
+		opt.block(stmt)
+	}
+
+	return stmt
+}
+
+func (opt *optimizer) expressions(exps []ast.Expression) {
+	for i, exp := range exps {
+		exps[i] = opt.expression(exp)
+	}
+}
+
+func (opt *optimizer) expression(exp ast.Expression) ast.Expression {
+	switch exp := exp.(type) {
+	case *ast.PrefixExpression:
+		exp.Right = opt.expression(exp.Right)
+		return foldPrefix(exp)
+	case *ast.InfixExpression:
+		exp.Left = opt.expression(exp.Left)
+		exp.Right = opt.expression(exp.Right)
+		return foldInfix(exp)
+	case *ast.IfExpression:
+		return opt.ifExpression(exp)
+	case *ast.ConditionalExpression:
+		exp.Condition = opt.expression(exp.Condition)
+		exp.Consequence = opt.expression(exp.Consequence)
+		exp.Alternative = opt.expression(exp.Alternative)
+
+		if truthy, ok := constantTruthiness(exp.Condition); ok {
+			if truthy {
+				return exp.Consequence
+			}
+
+			return exp.Alternative
+		}
+
+		return exp
+	case *ast.FunctionLiteral:
+		opt.function(exp)
+		return exp
+	case *ast.CallExpression:
+		exp.Function = opt.expression(exp.Function)
+		opt.expressions(exp.Arguments)
+
+		for _, keyword := range exp.Keywords {
+			keyword.Value = opt.expression(keyword.Value)
+		}
+
+		if inlined := opt.inline(exp); inlined != nil {
+			return opt.expression(inlined)
+		}
+
+		return exp
+	case *ast.SpreadExpression:
+		exp.Value = opt.expression(exp.Value)
+		return exp
+	case *ast.ArrayLiteral:
+		opt.expressions(exp.Elements)
+		return exp
+	case *ast.HashLiteral:
+		opt.expressions(exp.Keys)
+		opt.expressions(exp.Values)
+		return exp
+	case *ast.IndexExpression:
+		exp.Left = opt.expression(exp.Left)
+		exp.Index = opt.expression(exp.Index)
+		return exp
+	case *ast.SliceExpression:
+		exp.Left = opt.expression(exp.Left)
+		exp.Start = opt.optionalExpression(exp.Start)
+		exp.End = opt.optionalExpression(exp.End)
+		exp.Step = opt.optionalExpression(exp.Step)
+		return exp
+	case *ast.MemberExpression:
+		exp.Left = opt.expression(exp.Left)
+		return exp
+	case *ast.ImportExpression:
+		exp.Path = opt.expression(exp.Path)
+		return exp
+	case *ast.TryExpression:
+		opt.block(exp.Block)
+		opt.block(exp.Catch)
+		opt.block(exp.Finally)
+		return exp
+	case *ast.MatchExpression:
+		exp.Subject = opt.expression(exp.Subject)
+
+		for _, arm := range exp.Arms {
+			arm.Guard = opt.optionalExpression(arm.Guard)
+			arm.Body = opt.expression(arm.Body)
+		}
+
+		return exp
+	case *ast.SwitchExpression:
+		exp.Subject = opt.expression(exp.Subject)
+
+		for _, switchCase := range exp.Cases {
+			opt.expressions(switchCase.Values)
+			opt.block(switchCase.Body)
+		}
+
+		opt.block(exp.Default)
+		return exp
+	default:
+		return exp
+	}
+}
+
+func (opt *optimizer) optionalExpression(exp ast.Expression) ast.Expression {
+	if exp == nil {
+		return nil
+	}
+
+	return opt.expression(exp)
+}
+
+func (opt *optimizer) function(fnLiteral *ast.FunctionLiteral) {
+	for i, defaultValue := range fnLiteral.Defaults {
+		fnLiteral.Defaults[i] = opt.optionalExpression(defaultValue)
+	}
+
+	opt.block(fnLiteral.Body)
+}
+
+// Drops the branch that is never taken. The condition is kept, so the if
+// expression still evaluates to null without an alternative.
+func (opt *optimizer) ifExpression(ifExp *ast.IfExpression) ast.Expression {
+	ifExp.Condition = opt.expression(ifExp.Condition)
+	opt.block(ifExp.Consequence)
+	opt.block(ifExp.Alternative)
+	truthy, ok := constantTruthiness(ifExp.Condition)
+
+	if !ok {
+		return ifExp
+	}
+
+	position := ifExp.Condition.Pos()
+
+	if !truthy && ifExp.Alternative == nil {
+		ifExp.Condition = newBoolean(position, false)
+		ifExp.Consequence = &ast.BlockStatement{Token: ifExp.Consequence.Token}
+		return ifExp
+	}
+
+	if !truthy {
+		ifExp.Consequence = ifExp.Alternative
+	}
+
+	ifExp.Condition = newBoolean(position, true)
+	ifExp.Alternative = nil
+	return ifExp
+}
+
+// Truthiness reports whether the expression is truthy if its operators fold to
+// a constant. The expression itself is left unchanged.
+func Truthiness(exp ast.Expression) (bool, bool) {
+	return constantTruthiness(fold(exp))
+}
+
+// Folds the operators of a copy of the expression
+func fold(exp ast.Expression) ast.Expression {
+	switch exp := exp.(type) {
+	case *ast.PrefixExpression:
+		copied := *exp
+		copied.Right = fold(exp.Right)
+		return foldPrefix(&copied)
+	case *ast.InfixExpression:
+		copied := *exp
+		copied.Left = fold(exp.Left)
+		copied.Right = fold(exp.Right)
+		return foldInfix(&copied)
+	default:
+		return exp
+	}
+}
+
+// Literals are truthy unless they are false
+func constantTruthiness(exp ast.Expression) (bool, bool) {
+	switch exp := exp.(type) {
+	case *ast.BooleanLiteral:
+		return exp.Value, true
+	case *ast.IntegerLiteral, *ast.StringLiteral:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// Folds the operators the same way the evaluator applies them. Operations that
+// fail at runtime are left alone, so they still raise their error.
+func foldPrefix(prefixExp *ast.PrefixExpression) ast.Expression {
+	position := prefixExp.Token.Position
+
+	switch right := prefixExp.Right.(type) {
+	case *ast.IntegerLiteral:
+		switch prefixExp.Operator {
+		case "-":
+			return newInteger(position, -right.Value)
+		case "!":
+			return newBoolean(position, false)
+		}
+	case *ast.StringLiteral:
+		if prefixExp.Operator == "!" {
+			return newBoolean(position, false)
+		}
+	case *ast.BooleanLiteral:
+		if prefixExp.Operator == "!" {
+			return newBoolean(position, !right.Value)
+		}
+	}
+
+	return prefixExp
+}
+
+func foldInfix(infixExp *ast.InfixExpression) ast.Expression {
+	position := infixExp.Token.Position
+
+	if infixExp.Operator == "??" {
+		if _, ok := constantTruthiness(infixExp.Left); ok {
+			return infixExp.Left
+		}
+
+		return infixExp
+	}
+
+	switch left := infixExp.Left.(type) {
+	case *ast.IntegerLiteral:
+		if right, ok := infixExp.Right.(*ast.IntegerLiteral); ok {
+			return foldIntegerInfix(infixExp, left.Value, right.Value)
+		}
+	case *ast.StringLiteral:
+		if right, ok := infixExp.Right.(*ast.StringLiteral); ok {
+			if infixExp.Operator == "+" {
+				return newString(position, left.Value+right.Value)
+			}
+
+			return infixExp
+		}
+	case *ast.BooleanLiteral:
+		if right, ok := infixExp.Right.(*ast.BooleanLiteral); ok {
+			switch infixExp.Operator {
+			case "==":
+				return newBoolean(position, left.Value == right.Value)
+			case "!=":
+				return newBoolean(position, left.Value != right.Value)
+			}
+
+			return infixExp
+		}
+	}
+
+	// Literals of different types are never equal
+	if _, ok := constantTruthiness(infixExp.Left); ok {
+		if _, ok := constantTruthiness(infixExp.Right); ok {
+			switch infixExp.Operator {
+			case "==":
+				return newBoolean(position, false)
+			case "!=":
+				return newBoolean(position, true)
+			}
+		}
+	}
+
+	return infixExp
+}
+
+func foldIntegerInfix(infixExp *ast.InfixExpression, left, right int64) ast.Expression {
+	position := infixExp.Token.Position
+
+	switch infixExp.Operator {
+	case "+":
+		return newInteger(position, left+right)
+	case "-":
+		return newInteger(position, left-right)
+	case "*":
+		return newInteger(position, left*right)
+	case "/":
+		if right != 0 {
+			return newInteger(position, left/right)
+		}
+	case "<":
+		return newBoolean(position, left < right)
+	case ">":
+		return newBoolean(position, left > right)
+	case "==":
+		return newBoolean(position, left == right)
+	case "!=":
+		return newBoolean(position, left != right)
+	}
+
+	return infixExp
+}
+
+func newInteger(position token.Position, value int64) *ast.IntegerLiteral {
+	literal := strconv.FormatInt(value, 10)
+	return &ast.IntegerLiteral{Token: token.Token{Type: token.Int, Literal: literal, Position: position}, Value: value}
+}
+
+func newString(position token.Position, value string) *ast.StringLiteral {
+	return &ast.StringLiteral{Token: token.Token{Type: token.String, Literal: value, Position: position}, Value: value}
+}
+
+func newBoolean(position token.Position, value bool) *ast.BooleanLiteral {
+	tok := token.Token{Type: token.False, Literal: "false", Position: position}
+
+	if value {
+		tok = token.Token{Type: token.True, Literal: "true", Position: position}
+	}
+
+	return &ast.BooleanLiteral{Token: tok, Value: value}
+}
@@ -0,0 +1,140 @@
+package optimizer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/optimizer"
+	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantFolding(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"60 * 60 * 24", "86400"},
+		{"1 + 2 * 3 - 4 / 2", "5"},
+		{"-(2 + 3)", "-5"},
+		{"!true", "false"},
+		{"!5", "false"},
+		{"1 < 2", "true"},
+		{"3 == 4", "false"},
+		{"true != false", "true"},
+		{"1 == true", "false"},
+		{`"foo" + "bar"`, "foobar"},
+		{"x + 1 * 2", "(x + 2)"},
+		{"x * 2 * 3", "((x * 2) * 3)"},
+		{"1 / 0", "(1 / 0)"},
+		{`"a" == "a"`, "(a == a)"},
+		{`1 + "a"`, "(1 + a)"},
+		{"-true", "(-true)"},
+		{"5 ?? x", "5"},
+		{"1 < 2 ? a : b", "a"},
+		{"fn(x) { x * (2 + 3) }", "fn(x) (x * 5)"},
+		{"[1 + 1, {2 * 2: 3 - 3}[4]]", "[2, ({4: 0}[4])]"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, optimize(t, test.input), test.input)
+	}
+}
+
+func TestDeadBranchElimination(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"if (true) { a } else { b }", "a"},
+		{"if (1 > 2) { a } else { b }", "b"},
+		{"if (false) { a }; b", "b"},
+		{"b; if (false) { a }", "b\niffalse "},
+		{"let x = if (1 < 2) { a } else { b }", "let x = iftrue a;"},
+		{"let x = if (false) { a }", "let x = iffalse ;"},
+		{"fn(c) { if (true) { let y = 1; y } else { c } }", "fn(c) let y = 1;y"},
+		{"if (true) { fn f() { 1 } f() }", "iftrue fn f() 1f()"},
+		{"if (x) { a } else { b }", "ifx a else b"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, optimize(t, test.input), test.input)
+	}
+}
+
+func TestInlining(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let double = fn(x) { x * 2 }; double(21)", "let double = fn(x) (x * 2);\n42"},
+		{"let add = fn(a, b) { a + b }; add(x, 1)", "let add = fn(a, b) (a + b);\n(x + 1)"},
+		{"let square = fn(x) { x * x }; square(y)", "let square = fn(x) (x * x);\n(y * y)"},
+		{"fn double(x) { x * 2 } double(y)", "fn double(x) (x * 2)\n(y * 2)"},
+		{"double(y); fn double(x) { x * 2 }", "(y * 2)\nfn double(x) (x * 2)"},
+		{"let f = fn() { double(1) }; let double = fn(x) { x * 2 }", "let f = fn() double(1);\nlet double = fn(x) (x * 2);"},
+		{"let double = fn(x) { x * 2 }; fn f() { double(1) }", "let double = fn(x) (x * 2);\nfn f() double(1)"},
+		{"let double = fn(x) { x * 2 }; let f = fn() { double(1) }", "let double = fn(x) (x * 2);\nlet f = fn() 2;"},
+		{"let double = fn(x) { x * 2 }; double(y + 1)", "let double = fn(x) (x * 2);\ndouble((y + 1))"},
+		{"let double = fn(x) { x * 2 }; double(1, 2)", "let double = fn(x) (x * 2);\ndouble(1, 2)"},
+		{"let double = fn(x) { x * 2 }; let double = 5; double(1)", "let double = fn(x) (x * 2);\nlet double = 5;\ndouble(1)"},
+		{"let f = fn(x) { f(x) }; f(1)", "let f = fn(x) f(x);\nf(1)"},
+		{"let f = fn(x) { x + y }; f(1)", "let f = fn(x) (x + y);\nf(1)"},
+		{"let f = fn(a, b) { b - a }; f(1, 2)", "let f = fn(a, b) (b - a);\nf(1, 2)"},
+		{"let f = fn(a, b) { a }; f(1, 2)", "let f = fn(a, b) a;\nf(1, 2)"},
+		{"let f = fn(x = 1) { x }; f(2)", "let f = fn(x = 1) x;\nf(2)"},
+		{"let f = fn(x) { let y = x; y }; f(2)", "let f = fn(x) let y = x;y;\nf(2)"},
+		{"let inc = fn(x) { x + 1 }; 1 |> inc", "let inc = fn(x) (x + 1);\n2"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, optimize(t, test.input), test.input)
+	}
+}
+
+func TestTruthiness(t *testing.T) {
+	tests := []struct {
+		input    string
+		truthy   bool
+		constant bool
+	}{
+		{"true", true, true},
+		{"1 < 2", true, true},
+		{"!(2 > 1)", false, true},
+		{"\"a\" + \"b\"", true, true},
+		{"1 == true", false, true},
+		{"x == 1", false, false},
+		{"1 / 0", false, false},
+	}
+
+	for _, test := range tests {
+		lex := lexer.NewLexer(test.input)
+		par := parser.NewParser(lex)
+		program := par.ParseProgram()
+		assert.Empty(t, par.Errors(), test.input)
+
+		exp := program.Statements[0].(*ast.ExpressionStatement).Expression
+		written := exp.String()
+		truthy, constant := optimizer.Truthiness(exp)
+		assert.Equal(t, test.truthy, truthy, test.input)
+		assert.Equal(t, test.constant, constant, test.input)
+		assert.Equal(t, written, exp.String(), test.input)
+	}
+}
+
+func optimize(t *testing.T, input string) string {
+	lex := lexer.NewLexer(input)
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+	assert.Empty(t, par.Errors(), input)
+	optimizer.Optimize(program)
+	lines := []string{}
+
+	for _, stmt := range program.Statements {
+		lines = append(lines, stmt.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,22 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/benchmarks"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/parser"
+)
+
+func BenchmarkParser(b *testing.B) {
+	for _, program := range benchmarks.Programs() {
+		b.Run(program.Name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				par := parser.NewParser(lexer.NewLexer(program.Source))
+				par.ParseProgram()
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"fmt"
+
 	"github.com/henningstorck/monkey-interpreter/ast"
 	"github.com/henningstorck/monkey-interpreter/token"
 )
@@ -54,13 +56,116 @@ func (par *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 }
 
 func (par *Parser) parseGroupedExpression() ast.Expression {
+	start := par.curToken
+
+	if par.peekTokenIs(token.RParen) {
+		par.nextToken()
+
+		if !par.expectPeek(token.Arrow) {
+			return nil
+		}
+
+		return par.parseLambdaBody(start, []ast.Expression{})
+	}
+
 	par.nextToken()
 	exp := par.parseExpression(Lowest)
 
-	if !par.expectPeek(token.RParen) {
+	if !par.peekTokenIs(token.Comma) {
+		if !par.expectPeek(token.RParen) {
+			return nil
+		}
+
+		return exp
+	}
+
+	// A list of expressions is only valid as the parameters of a lambda
+	params := []ast.Expression{exp}
+
+	for par.peekTokenIs(token.Comma) {
+		par.nextToken()
+		par.nextToken()
+		params = append(params, par.parseExpression(Lowest))
+	}
+
+	if !par.expectPeek(token.RParen) || !par.expectPeek(token.Arrow) {
+		return nil
+	}
+
+	return par.parseLambdaBody(start, params)
+}
+
+// Parses lambdas with a single parameter like x => x * 2
+func (par *Parser) parseLambda(param ast.Expression) ast.Expression {
+	if param == nil {
 		return nil
 	}
 
+	start := token.Token{Position: param.Pos()}
+	return par.parseLambdaBody(start, []ast.Expression{param})
+}
+
+// Lambdas are functions returning the value of a single expression
+func (par *Parser) parseLambdaBody(start token.Token, params []ast.Expression) ast.Expression {
+	fnLiteral := &ast.FunctionLiteral{
+		Token:      token.Token{Type: token.Function, Literal: "fn", Position: start.Position},
+		Parameters: []*ast.Identifier{},
+		Defaults:   []ast.Expression{},
+	}
+
+	for i, param := range params {
+		if spread, ok := param.(*ast.SpreadExpression); ok && i == len(params)-1 {
+			param = spread.Value
+
+			if ident, ok := param.(*ast.Identifier); ok {
+				fnLiteral.Rest = ident
+				continue
+			}
+		}
+
+		ident, ok := param.(*ast.Identifier)
+
+		if param == nil {
+			return nil
+		} else if !ok {
+			msg := fmt.Sprintf("invalid lambda parameter: %s", param)
+			par.errors = append(par.errors, msg)
+			return nil
+		}
+
+		fnLiteral.Parameters = append(fnLiteral.Parameters, ident)
+		fnLiteral.Defaults = append(fnLiteral.Defaults, nil)
+	}
+
+	arrow := par.curToken
+	par.nextToken()
+	body := par.parseExpression(Lowest)
+
+	fnLiteral.Body = &ast.BlockStatement{
+		Token:      arrow,
+		Statements: []ast.Statement{&ast.ExpressionStatement{Token: arrow, Expression: body}},
+	}
+
+	return fnLiteral
+}
+
+// A pipeline passes its left side as the first argument to the call on its
+// right side, so it is parsed as that call
+func (par *Parser) parsePipelineExpression(left ast.Expression) ast.Expression {
+	exp := &ast.CallExpression{Token: par.curToken}
+	precedence := par.curPrecedence()
+	par.nextToken()
+	right := par.parseExpression(precedence)
+
+	if call, ok := right.(*ast.CallExpression); ok {
+		exp.Function = call.Function
+		exp.Arguments = append([]ast.Expression{left}, call.Arguments...)
+		exp.Keywords = call.Keywords
+	} else {
+		exp.Function = right
+		exp.Arguments = []ast.Expression{left}
+	}
+
 	return exp
 }
 
@@ -87,6 +192,12 @@ func (par *Parser) parseIfExpression() ast.Expression {
 	if par.peekTokenIs(token.Else) {
 		par.nextToken()
 
+		if par.peekTokenIs(token.If) {
+			par.nextToken()
+			exp.Alternative = par.parseElseIf()
+			return exp
+		}
+
 		if !par.expectPeek(token.LBrace) {
 			return nil
 		}
@@ -97,13 +208,199 @@ func (par *Parser) parseIfExpression() ast.Expression {
 	return exp
 }
 
+// An else if chain is a nested if expression as the only statement of the
+// alternative block
+func (par *Parser) parseElseIf() *ast.BlockStatement {
+	tok := par.curToken
+	nested := par.parseIfExpression()
+
+	if nested == nil {
+		return nil
+	}
+
+	return &ast.BlockStatement{
+		Token:      tok,
+		Statements: []ast.Statement{&ast.ExpressionStatement{Token: tok, Expression: nested}},
+	}
+}
+
+func (par *Parser) parseSwitchExpression() ast.Expression {
+	exp := &ast.SwitchExpression{Token: par.curToken}
+
+	if !par.expectPeek(token.LParen) {
+		return nil
+	}
+
+	par.nextToken()
+	exp.Subject = par.parseExpression(Lowest)
+
+	if !par.expectPeek(token.RParen) || !par.expectPeek(token.LBrace) {
+		return nil
+	}
+
+	par.nextToken()
+
+	for !par.curTokenIs(token.RBrace) {
+		switch {
+		case par.curTokenIs(token.Case):
+			switchCase := par.parseSwitchCase()
+
+			if switchCase == nil {
+				return nil
+			}
+
+			exp.Cases = append(exp.Cases, switchCase)
+		case par.curTokenIs(token.Default) && exp.Default == nil:
+			if !par.expectPeek(token.Colon) {
+				return nil
+			}
+
+			exp.Default = par.parseCaseBody()
+		case par.curTokenIs(token.Default):
+			par.errors = append(par.errors, "switch has more than one default case")
+			return nil
+		default:
+			msg := fmt.Sprintf("expected case or default, got %s instead", par.curToken.Type)
+			par.errors = append(par.errors, msg)
+			return nil
+		}
+	}
+
+	return exp
+}
+
+func (par *Parser) parseSwitchCase() *ast.SwitchCase {
+	switchCase := &ast.SwitchCase{Token: par.curToken}
+	par.nextToken()
+	switchCase.Values = append(switchCase.Values, par.parseExpression(Lowest))
+
+	for par.peekTokenIs(token.Comma) {
+		par.nextToken()
+		par.nextToken()
+		switchCase.Values = append(switchCase.Values, par.parseExpression(Lowest))
+	}
+
+	if !par.expectPeek(token.Colon) {
+		return nil
+	}
+
+	switchCase.Body = par.parseCaseBody()
+	return switchCase
+}
+
+// Parses the statements up to the next case, default or the end of the switch
+func (par *Parser) parseCaseBody() *ast.BlockStatement {
+	blockStmt := &ast.BlockStatement{Token: par.curToken}
+	blockStmt.Statements = []ast.Statement{}
+	par.nextToken()
+
+	for !par.curTokenIs(token.Case) && !par.curTokenIs(token.Default) &&
+		!par.curTokenIs(token.RBrace) && !par.curTokenIs(token.EOF) {
+		stmt := par.parseStatement()
+		blockStmt.Statements = append(blockStmt.Statements, stmt)
+		par.nextToken()
+	}
+
+	return blockStmt
+}
+
+func (par *Parser) parseTryExpression() ast.Expression {
+	exp := &ast.TryExpression{Token: par.curToken}
+
+	if !par.expectPeek(token.LBrace) {
+		return nil
+	}
+
+	exp.Block = par.parseBlockStatement()
+
+	if par.peekTokenIs(token.Catch) {
+		par.nextToken()
+
+		if !par.expectPeek(token.LParen) {
+			return nil
+		}
+
+		if !par.expectPeek(token.Ident) {
+			return nil
+		}
+
+		exp.Parameter = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+
+		if !par.expectPeek(token.RParen) {
+			return nil
+		}
+
+		if !par.expectPeek(token.LBrace) {
+			return nil
+		}
+
+		exp.Catch = par.parseBlockStatement()
+	}
+
+	if par.peekTokenIs(token.Finally) {
+		par.nextToken()
+
+		if !par.expectPeek(token.LBrace) {
+			return nil
+		}
+
+		exp.Finally = par.parseBlockStatement()
+	}
+
+	if exp.Catch == nil && exp.Finally == nil {
+		msg := fmt.Sprintf("expected catch or finally after try block, got %s instead", par.peekToken.Type)
+		par.errors = append(par.errors, msg)
+		return nil
+	}
+
+	return exp
+}
+
 func (par *Parser) parseCallExpression(fn ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{
 		Token:    par.curToken,
 		Function: fn,
 	}
 
-	exp.Arguments = par.parseExpressionList(token.RParen)
+	if !par.parseCallArguments(exp) {
+		return nil
+	}
+
+	return exp
+}
+
+// Arguments are positional ones followed by keyword arguments like name: value
+func (par *Parser) parseCallArguments(exp *ast.CallExpression) bool {
+	exp.Arguments = []ast.Expression{}
+
+	for !par.peekTokenIs(token.RParen) {
+		par.nextToken()
+
+		if par.curTokenIs(token.Ident) && par.peekTokenIs(token.Colon) {
+			keyword := &ast.KeywordArgument{Name: &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}}
+			par.nextToken()
+			par.nextToken()
+			keyword.Value = par.parseExpression(Lowest)
+			exp.Keywords = append(exp.Keywords, keyword)
+		} else if len(exp.Keywords) > 0 {
+			par.errors = append(par.errors, "positional argument follows keyword argument")
+			return false
+		} else {
+			exp.Arguments = append(exp.Arguments, par.parseExpression(Lowest))
+		}
+
+		if !par.peekTokenIs(token.RParen) && !par.expectPeek(token.Comma) {
+			return false
+		}
+	}
+
+	return par.expectPeek(token.RParen)
+}
+
+func (par *Parser) parseSpreadExpression() ast.Expression {
+	exp := &ast.SpreadExpression{Token: par.curToken}
+	par.nextToken()
+	exp.Value = par.parseExpression(Lowest)
 	return exp
 }
 
@@ -113,8 +410,44 @@ func (par *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 		Left:  left,
 	}
 
+	if !par.peekTokenIs(token.Colon) {
+		par.nextToken()
+		exp.Index = par.parseExpression(Lowest)
+	}
+
+	if par.peekTokenIs(token.Colon) {
+		return par.parseSliceExpression(exp.Token, left, exp.Index)
+	}
+
+	if !par.expectPeek(token.RBracket) {
+		return nil
+	}
+
+	return exp
+}
+
+func (par *Parser) parseSliceExpression(tok token.Token, left, start ast.Expression) ast.Expression {
+	exp := &ast.SliceExpression{
+		Token: tok,
+		Left:  left,
+		Start: start,
+	}
+
 	par.nextToken()
-	exp.Index = par.parseExpression(Lowest)
+
+	if !par.peekTokenIs(token.Colon) && !par.peekTokenIs(token.RBracket) {
+		par.nextToken()
+		exp.End = par.parseExpression(Lowest)
+	}
+
+	if par.peekTokenIs(token.Colon) {
+		par.nextToken()
+
+		if !par.peekTokenIs(token.RBracket) {
+			par.nextToken()
+			exp.Step = par.parseExpression(Lowest)
+		}
+	}
 
 	if !par.expectPeek(token.RBracket) {
 		return nil
@@ -123,6 +456,70 @@ func (par *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return exp
 }
 
+func (par *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{
+		Token: par.curToken,
+		Left:  left,
+	}
+
+	if !par.expectPeek(token.Ident) {
+		return nil
+	}
+
+	exp.Property = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+	return exp
+}
+
+func (par *Parser) parseConditionalExpression(condition ast.Expression) ast.Expression {
+	exp := &ast.ConditionalExpression{Token: par.curToken, Condition: condition}
+	par.nextToken()
+	exp.Consequence = par.parseExpression(Lowest)
+
+	if !par.expectPeek(token.Colon) {
+		return nil
+	}
+
+	// Parsing the alternative with a lower precedence makes chains right
+	// associative
+	par.nextToken()
+	exp.Alternative = par.parseExpression(Ternary - 1)
+	return exp
+}
+
+// Parses a?.b, a?.[i] and a?.[i:j]
+func (par *Parser) parseOptionalChain(left ast.Expression) ast.Expression {
+	if !par.peekTokenIs(token.LBracket) {
+		exp, ok := par.parseMemberExpression(left).(*ast.MemberExpression)
+
+		if !ok {
+			return nil
+		}
+
+		exp.Optional = true
+		return exp
+	}
+
+	par.nextToken()
+
+	switch exp := par.parseIndexExpression(left).(type) {
+	case *ast.IndexExpression:
+		exp.Optional = true
+		return exp
+	case *ast.SliceExpression:
+		exp.Optional = true
+		return exp
+	default:
+		return nil
+	}
+}
+
+func (par *Parser) parseImportExpression() ast.Expression {
+	exp := &ast.ImportExpression{Token: par.curToken}
+	par.nextToken()
+	exp.Path = par.parseExpression(Prefix)
+	return exp
+}
+
 func (par *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	list := []ast.Expression{}
 
@@ -146,3 +543,96 @@ func (par *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 
 	return list
 }
+
+func (par *Parser) parseMatchExpression() ast.Expression {
+	exp := &ast.MatchExpression{Token: par.curToken}
+
+	if !par.expectPeek(token.LParen) {
+		return nil
+	}
+
+	par.nextToken()
+	exp.Subject = par.parseExpression(Lowest)
+
+	if !par.expectPeek(token.RParen) || !par.expectPeek(token.LBrace) {
+		return nil
+	}
+
+	for !par.peekTokenIs(token.RBrace) {
+		par.nextToken()
+		arm := &ast.MatchArm{Pattern: par.parsePattern()}
+
+		if arm.Pattern == nil {
+			return nil
+		}
+
+		if par.peekTokenIs(token.If) {
+			par.nextToken()
+			par.nextToken()
+			arm.Guard = par.parseExpression(Lambda)
+		}
+
+		if !par.expectPeek(token.Arrow) {
+			return nil
+		}
+
+		par.nextToken()
+		arm.Body = par.parseExpression(Lowest)
+		exp.Arms = append(exp.Arms, arm)
+
+		if !par.peekTokenIs(token.RBrace) && !par.expectPeek(token.Comma) {
+			return nil
+		}
+	}
+
+	if !par.expectPeek(token.RBrace) {
+		return nil
+	}
+
+	par.checkMatchArms(exp)
+	return exp
+}
+
+// Warns about arms shadowed by earlier ones and values no arm matches
+func (par *Parser) checkMatchArms(exp *ast.MatchExpression) {
+	exhaustive := false
+	literals := map[string]bool{}
+
+	for _, arm := range exp.Arms {
+		if exhaustive || literals[literalKey(arm.Pattern)] {
+			msg := fmt.Sprintf("unreachable match arm at %s: %s", arm.Pattern.Pos(), arm.Pattern.String())
+			par.warnings = append(par.warnings, msg)
+			continue
+		}
+
+		if arm.Guard != nil {
+			continue
+		}
+
+		switch pattern := arm.Pattern.(type) {
+		case *ast.Identifier:
+			exhaustive = true
+		case *ast.IntegerLiteral, *ast.StringLiteral, *ast.BooleanLiteral:
+			literals[literalKey(pattern)] = true
+			exhaustive = literals["BOOL true"] && literals["BOOL false"]
+		}
+	}
+
+	if !exhaustive {
+		msg := fmt.Sprintf("non-exhaustive match at %s: unmatched values evaluate to null", exp.Pos())
+		par.warnings = append(par.warnings, msg)
+	}
+}
+
+func literalKey(pattern ast.Pattern) string {
+	switch pattern := pattern.(type) {
+	case *ast.IntegerLiteral:
+		return fmt.Sprintf("INT %d", pattern.Value)
+	case *ast.StringLiteral:
+		return "STRING " + pattern.Value
+	case *ast.BooleanLiteral:
+		return fmt.Sprintf("BOOL %t", pattern.Value)
+	default:
+		return ""
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/parser"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -103,6 +105,170 @@ func TestParseIfElseExpression(t *testing.T) {
 	testLiteral(t, alternative.Expression, "y")
 }
 
+func TestParseElseIfExpression(t *testing.T) {
+	input := "if (x < y) { x } else if (x > y) { y } else { z }"
+	program := testParse(t, input)
+
+	assert.Len(t, program.Statements, 1)
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	assert.True(t, ok)
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+	assert.True(t, ok)
+	assert.Equal(t, "if(x < y) x else if(x > y) y else z", exp.String())
+
+	assert.Len(t, exp.Alternative.Statements, 1)
+	alternative, ok := exp.Alternative.Statements[0].(*ast.ExpressionStatement)
+	assert.True(t, ok)
+	nested, ok := alternative.Expression.(*ast.IfExpression)
+	assert.True(t, ok)
+	testInfixExpression(t, nested.Condition, "x", ">", "y")
+	assert.Equal(t, "z", nested.Alternative.String())
+}
+
+func TestParseSwitchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"switch (x) { case 1: a }", "switch x { case 1: a }"},
+		{"switch (x) { case 1, 2: a; b; case 3: c; default: d }", "switch x { case 1, 2: ab case 3: c default: d }"},
+		{"switch (x + 1) { default: let y = 2; }", "switch (x + 1) { default: let y = 2; }"},
+		{"switch (x) { case 1: }", "switch x { case 1:  }"},
+		{"switch (x) { }", "switch x { }"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		assert.Len(t, program.Statements, 1)
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		assert.True(t, ok)
+		exp, ok := stmt.Expression.(*ast.SwitchExpression)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, exp.String())
+	}
+}
+
+func TestParseSwitchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"switch x { }", "expected next token to be (, got IDENT instead"},
+		{"switch (x) { 1 }", "expected case or default, got INT instead"},
+		{"switch (x) { case 1 a }", "expected next token to be :, got IDENT instead"},
+		{"switch (x) { default: 1 default: 2 }", "switch has more than one default case"},
+		{"switch (x) { case 1: 2", "expected case or default, got EOF instead"},
+	}
+
+	for _, test := range tests {
+		par := parser.NewParser(lexer.NewLexer(test.input))
+		par.ParseProgram()
+		assert.Contains(t, par.Errors(), test.expected, test.input)
+	}
+}
+
+func TestParseTryExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { x } catch (e) { y }", "try x catch(e) y"},
+		{"try { x } finally { z }", "try x finally z"},
+		{"try { x } catch (err) { y } finally { z }", "try x catch(err) y finally z"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		assert.Len(t, program.Statements, 1)
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		assert.True(t, ok)
+		_, ok = stmt.Expression.(*ast.TryExpression)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, program.String())
+	}
+}
+
+func TestParseTryExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { x }", "expected catch or finally after try block, got EOF instead"},
+		{"try { x } catch { y }", "expected next token to be (, got { instead"},
+	}
+
+	for _, test := range tests {
+		par := parser.NewParser(lexer.NewLexer(test.input))
+		par.ParseProgram()
+		assert.Contains(t, par.Errors(), test.expected)
+	}
+}
+
+func TestParseMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { 1 => a, _ => b }", "match x { 1 => a, _ => b }"},
+		{"match (x) { -1 => a, _ => b, }", "match x { -1 => a, _ => b }"},
+		{`match (x) { "a" => 1, true => 2, n => n }`, "match x { a => 1, true => 2, n => n }"},
+		{"match (x) { n: int if n > 0 => n, _: string => 0, _ => -1 }", "match x { n: int if (n > 0) => n, _: string => 0, _ => (-1) }"},
+		{"match (x) { [a, ...rest] => a, {name: n: string, age} => n, _ => 0 }", "match x { [a, ...rest] => a, {name: n: string, age} => n, _ => 0 }"},
+		{"match (f(x)) { [1, [b]] => b, _ => 0 }", "match f(x) { [1, [b]] => b, _ => 0 }"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		assert.Len(t, program.Statements, 1)
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		assert.True(t, ok)
+		exp, ok := stmt.Expression.(*ast.MatchExpression)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, exp.String())
+	}
+}
+
+func TestParseMatchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match x { _ => 1 }", "expected next token to be (, got IDENT instead"},
+		{"match (x) { 1 2 }", "expected next token to be =>, got INT instead"},
+		{"match (x) { n: number => 1 }", "unknown type: number"},
+		{"match (x) { 1 => 1 _ => 2 }", "expected next token to be ,, got IDENT instead"},
+	}
+
+	for _, test := range tests {
+		par := parser.NewParser(lexer.NewLexer(test.input))
+		par.ParseProgram()
+		assert.Contains(t, par.Errors(), test.expected, test.input)
+	}
+}
+
+func TestParseMatchExpressionWarnings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"match (x) { 1 => a, _ => b }", []string{}},
+		{"match (x) { true => a, false => b }", []string{}},
+		{"match (x) { n if n > 1 => a, n => b }", []string{}},
+		{"match (x) { 1 => a }", []string{"non-exhaustive match at 1:1: unmatched values evaluate to null"}},
+		{"match (x) { n if n > 1 => a }", []string{"non-exhaustive match at 1:1: unmatched values evaluate to null"}},
+		{"match (x) { _ => a, 1 => b }", []string{"unreachable match arm at 1:21: 1"}},
+		{"match (x) { 1 => a, 1 => b, n => c }", []string{"unreachable match arm at 1:21: 1"}},
+		{"match (x) { true => a, false => b, _ => c }", []string{"unreachable match arm at 1:36: _"}},
+	}
+
+	for _, test := range tests {
+		par := parser.NewParser(lexer.NewLexer(test.input))
+		par.ParseProgram()
+		assert.Empty(t, par.Errors())
+		assert.Equal(t, test.expected, par.Warnings(), test.input)
+	}
+}
+
 func TestParseCallExpression(t *testing.T) {
 	input := "add(1, 2 * 3, 4 + 5);"
 	program := testParse(t, input)
@@ -142,6 +308,35 @@ func TestParseCallArguments(t *testing.T) {
 	}
 }
 
+func TestParseKeywordAndSpreadArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		keywords int
+	}{
+		{"f(1, y: 2)", "f(1, y: 2)", 1},
+		{"f(x: 1 + 2, y: g(3))", "f(x: (1 + 2), y: g(3))", 2},
+		{"f(...args)", "f(...args)", 0},
+		{"f(1, ...rest(xs), y: 2)", "f(1, ...rest(xs), y: 2)", 1},
+		{"[0, ...xs]", "[0, ...xs]", 0},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, stmt.String())
+
+		if exp, ok := stmt.Expression.(*ast.CallExpression); ok {
+			assert.Len(t, exp.Keywords, test.keywords)
+		}
+	}
+
+	par := parser.NewParser(lexer.NewLexer("f(x: 1, 2)"))
+	par.ParseProgram()
+	assert.Contains(t, par.Errors(), "positional argument follows keyword argument")
+}
+
 func TestParseIndexExpression(t *testing.T) {
 	input := "myArray[1 + 1];"
 	program := testParse(t, input)
@@ -153,6 +348,25 @@ func TestParseIndexExpression(t *testing.T) {
 	testInfixExpression(t, exp.Index, 1, "+", 1)
 }
 
+func TestParseSliceExpression(t *testing.T) {
+	input := "myArray[1:2:3]"
+	program := testParse(t, input)
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	assert.True(t, ok)
+	exp, ok := stmt.Expression.(*ast.SliceExpression)
+	assert.True(t, ok)
+	testIdentifier(t, exp.Left, "myArray")
+	testLiteral(t, exp.Start, 1)
+	testLiteral(t, exp.End, 2)
+	testLiteral(t, exp.Step, 3)
+
+	program = testParse(t, "myArray[:]")
+	exp = program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.SliceExpression)
+	assert.Nil(t, exp.Start)
+	assert.Nil(t, exp.End)
+	assert.Nil(t, exp.Step)
+}
+
 func testInfixExpression(t *testing.T, exp ast.Expression, leftValue any, operator string, rightValue any) {
 	infixExp, ok := exp.(*ast.InfixExpression)
 	assert.True(t, ok)
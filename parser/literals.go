@@ -36,54 +36,96 @@ func (par *Parser) parseBooleanLiteral() ast.Expression {
 func (par *Parser) parseFunctionLiteral() ast.Expression {
 	fnLiteral := &ast.FunctionLiteral{Token: par.curToken}
 
-	if !par.expectPeek(token.LParen) {
+	if !par.parseFunction(fnLiteral) {
 		return nil
 	}
 
-	fnLiteral.Parameters = par.parseFunctionParameters()
+	return fnLiteral
+}
+
+// Parses the parameters and the body following fn or its name
+func (par *Parser) parseFunction(fnLiteral *ast.FunctionLiteral) bool {
+	if !par.expectPeek(token.LParen) {
+		return false
+	}
+
+	if !par.parseFunctionParameters(fnLiteral) {
+		return false
+	}
+
+	if par.peekTokenIs(token.Colon) {
+		var ok bool
+		par.nextToken()
+
+		if fnLiteral.ReturnType, ok = par.parseType(); !ok {
+			return false
+		}
+	}
 
 	if !par.expectPeek(token.LBrace) {
-		return nil
+		return false
 	}
 
 	fnLiteral.Body = par.parseBlockStatement()
-	return fnLiteral
+	return true
 }
 
-func (par *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+func (par *Parser) parseFunctionParameters(fnLiteral *ast.FunctionLiteral) bool {
+	fnLiteral.Parameters = []*ast.Identifier{}
+	fnLiteral.Types = []string{}
+	fnLiteral.Defaults = []ast.Expression{}
 
-	if par.peekTokenIs(token.RParen) {
+	for !par.peekTokenIs(token.RParen) {
 		par.nextToken()
-		return identifiers
-	}
 
-	par.nextToken()
+		if par.curTokenIs(token.Ellipsis) {
+			if !par.expectPeek(token.Ident) {
+				return false
+			}
 
-	identifier := &ast.Identifier{
-		Token: par.curToken,
-		Value: par.curToken.Literal,
-	}
+			fnLiteral.Rest = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+			break
+		}
 
-	identifiers = append(identifiers, identifier)
+		if !par.curTokenIs(token.Ident) {
+			msg := fmt.Sprintf("expected a parameter, got %s instead", par.curToken.Type)
+			par.errors = append(par.errors, msg)
+			return false
+		}
 
-	for par.peekTokenIs(token.Comma) {
-		par.nextToken()
-		par.nextToken()
+		param := &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+		paramType := ""
+		var defaultValue ast.Expression
+
+		if par.peekTokenIs(token.Colon) {
+			var ok bool
+			par.nextToken()
 
-		identifier := &ast.Identifier{
-			Token: par.curToken,
-			Value: par.curToken.Literal,
+			if paramType, ok = par.parseType(); !ok {
+				return false
+			}
 		}
 
-		identifiers = append(identifiers, identifier)
-	}
+		if par.peekTokenIs(token.Assign) {
+			par.nextToken()
+			par.nextToken()
+			defaultValue = par.parseExpression(Lowest)
+		} else if count := len(fnLiteral.Defaults); count > 0 && fnLiteral.Defaults[count-1] != nil {
+			msg := fmt.Sprintf("required parameter %s follows parameter with default value", param.Value)
+			par.errors = append(par.errors, msg)
+			return false
+		}
 
-	if !par.expectPeek(token.RParen) {
-		return nil
+		fnLiteral.Parameters = append(fnLiteral.Parameters, param)
+		fnLiteral.Types = append(fnLiteral.Types, paramType)
+		fnLiteral.Defaults = append(fnLiteral.Defaults, defaultValue)
+
+		if !par.peekTokenIs(token.RParen) && !par.expectPeek(token.Comma) {
+			return false
+		}
 	}
 
-	return identifiers
+	return par.expectPeek(token.RParen)
 }
 
 func (par *Parser) parseStringLiteral() ast.Expression {
@@ -98,3 +140,31 @@ func (par *Parser) parseArrayLiteral() ast.Expression {
 	arrLiteral.Elements = par.parseExpressionList(token.RBracket)
 	return arrLiteral
 }
+
+func (par *Parser) parseHashLiteral() ast.Expression {
+	hashLiteral := &ast.HashLiteral{Token: par.curToken}
+
+	for !par.peekTokenIs(token.RBrace) {
+		par.nextToken()
+		key := par.parseExpression(Lowest)
+
+		if !par.expectPeek(token.Colon) {
+			return nil
+		}
+
+		par.nextToken()
+		value := par.parseExpression(Lowest)
+		hashLiteral.Keys = append(hashLiteral.Keys, key)
+		hashLiteral.Values = append(hashLiteral.Values, value)
+
+		if !par.peekTokenIs(token.RBrace) && !par.expectPeek(token.Comma) {
+			return nil
+		}
+	}
+
+	if !par.expectPeek(token.RBrace) {
+		return nil
+	}
+
+	return hashLiteral
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/parser"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -77,6 +79,82 @@ func TestParseFunctionParameters(t *testing.T) {
 	}
 }
 
+func TestParseDefaultAndRestParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		rest     string
+	}{
+		{"fn(x, y = 10) { x + y }", "fn(x, y = 10) (x + y)", ""},
+		{"fn(x = 1, y = x * 2) { y }", "fn(x = 1, y = (x * 2)) y", ""},
+		{"fn(first, ...others) { others }", "fn(first, ...others) others", "others"},
+		{"fn(...all) { all }", "fn(...all) all", "all"},
+		{"fn(x, y = 2, ...others) { x }", "fn(x, y = 2, ...others) x", "others"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		assert.True(t, ok)
+		fnLiteral, ok := stmt.Expression.(*ast.FunctionLiteral)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, fnLiteral.String())
+		assert.Len(t, fnLiteral.Defaults, len(fnLiteral.Parameters))
+
+		if test.rest == "" {
+			assert.Nil(t, fnLiteral.Rest)
+		} else {
+			assert.Equal(t, test.rest, fnLiteral.Rest.Value)
+		}
+	}
+}
+
+func TestParseTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		input      string
+		expected   string
+		types      []string
+		returnType string
+	}{
+		{"fn(a: int, b: string): bool { a }", "fn(a: int, b: string): bool a", []string{"int", "string"}, "bool"},
+		{"fn(a, b: array) { a }", "fn(a, b: array) a", []string{"", "array"}, ""},
+		{"fn(a: int = 1, ...rest): fn { a }", "fn(a: int = 1, ...rest): fn a", []string{"int"}, "fn"},
+		{"fn(): null { }", "fn(): null ", []string{}, "null"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		assert.True(t, ok)
+		fnLiteral, ok := stmt.Expression.(*ast.FunctionLiteral)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, fnLiteral.String())
+		assert.Equal(t, test.types, fnLiteral.Types)
+		assert.Equal(t, test.returnType, fnLiteral.ReturnType)
+	}
+}
+
+func TestParseFunctionParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn(x = 1, y) { }", "required parameter y follows parameter with default value"},
+		{"fn(...xs, y) { }", "expected next token to be ), got , instead"},
+		{"fn(1) { }", "expected a parameter, got INT instead"},
+		{"fn(x y) { }", "expected next token to be ,, got IDENT instead"},
+		{"fn(x: integer) { }", "unknown type: integer"},
+		{`fn(x: "int") { }`, "unknown type: int"},
+		{"fn(x): { }", "unknown type: {"},
+	}
+
+	for _, test := range tests {
+		par := parser.NewParser(lexer.NewLexer(test.input))
+		par.ParseProgram()
+		assert.Contains(t, par.Errors(), test.expected, test.input)
+	}
+}
+
 func TestParseStringLiteral(t *testing.T) {
 	input := `"hello world";`
 	program := testParse(t, input)
@@ -102,6 +180,28 @@ func TestParseArrayLiteral(t *testing.T) {
 	testInfixExpression(t, arrLiteral.Elements[2], 3, "+", 3)
 }
 
+func TestParseHashLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"one": 1, "two": 2}`, "{one: 1, two: 2}"},
+		{"{}", "{}"},
+		{`{"sum": 1 + 2, true: x, 3: fn(y) { y }}`, "{sum: (1 + 2), true: x, 3: fn(y) y}"},
+		{`{"a": 1,}`, "{a: 1}"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		assert.Len(t, program.Statements, 1)
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		assert.True(t, ok)
+		hashLiteral, ok := stmt.Expression.(*ast.HashLiteral)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, hashLiteral.String())
+	}
+}
+
 func testLiteral(t *testing.T, exp ast.Expression, expected any) {
 	switch value := expected.(type) {
 	case int:
@@ -16,8 +16,12 @@ type (
 const (
 	_ int = iota
 	Lowest
+	Lambda
+	Ternary
+	Coalesce
 	Equals
 	LessGreater
+	Pipeline
 	Sum
 	Product
 	Prefix
@@ -26,21 +30,29 @@ const (
 )
 
 var precedences = map[token.TokenType]int{
-	token.Eq:          Equals,
-	token.NotEq:       Equals,
-	token.LessThan:    LessGreater,
-	token.GreaterThan: LessGreater,
-	token.Plus:        Sum,
-	token.Minus:       Sum,
-	token.Slash:       Product,
-	token.Asterisk:    Product,
-	token.LParen:      Call,
-	token.LBracket:    Index,
+	token.Eq:            Equals,
+	token.NotEq:         Equals,
+	token.LessThan:      LessGreater,
+	token.GreaterThan:   LessGreater,
+	token.Pipe:          Pipeline,
+	token.Arrow:         Lambda,
+	token.Question:      Ternary,
+	token.NullCoalesce:  Coalesce,
+	token.OptionalChain: Index,
+	token.Plus:          Sum,
+	token.Minus:         Sum,
+	token.Slash:         Product,
+	token.Asterisk:      Product,
+	token.LParen:        Call,
+	token.LBracket:      Index,
+	token.Dot:           Index,
 }
 
 type Parser struct {
-	lex    *lexer.Lexer
-	errors []string
+	lex      *lexer.Lexer
+	errors   []string
+	warnings []string
+	comments []*ast.Comment
 
 	curToken  token.Token
 	peekToken token.Token
@@ -51,8 +63,9 @@ type Parser struct {
 
 func NewParser(lex *lexer.Lexer) *Parser {
 	par := &Parser{
-		lex:    lex,
-		errors: []string{},
+		lex:      lex,
+		errors:   []string{},
+		warnings: []string{},
 	}
 
 	par.populateCurAndPeekToken()
@@ -66,9 +79,15 @@ func NewParser(lex *lexer.Lexer) *Parser {
 	par.registerPrefix(token.Minus, par.parsePrefixExpression)
 	par.registerPrefix(token.LParen, par.parseGroupedExpression)
 	par.registerPrefix(token.If, par.parseIfExpression)
+	par.registerPrefix(token.Try, par.parseTryExpression)
+	par.registerPrefix(token.Import, par.parseImportExpression)
+	par.registerPrefix(token.Match, par.parseMatchExpression)
+	par.registerPrefix(token.Switch, par.parseSwitchExpression)
+	par.registerPrefix(token.Ellipsis, par.parseSpreadExpression)
 	par.registerPrefix(token.Function, par.parseFunctionLiteral)
 	par.registerPrefix(token.String, par.parseStringLiteral)
 	par.registerPrefix(token.LBracket, par.parseArrayLiteral)
+	par.registerPrefix(token.LBrace, par.parseHashLiteral)
 
 	par.infixParseFns = make(map[token.TokenType]infixParseFn)
 	par.registerInfix(token.Plus, par.parseInfixExpression)
@@ -81,6 +100,12 @@ func NewParser(lex *lexer.Lexer) *Parser {
 	par.registerInfix(token.GreaterThan, par.parseInfixExpression)
 	par.registerInfix(token.LParen, par.parseCallExpression)
 	par.registerInfix(token.LBracket, par.parseIndexExpression)
+	par.registerInfix(token.Dot, par.parseMemberExpression)
+	par.registerInfix(token.Pipe, par.parsePipelineExpression)
+	par.registerInfix(token.Arrow, par.parseLambda)
+	par.registerInfix(token.Question, par.parseConditionalExpression)
+	par.registerInfix(token.NullCoalesce, par.parseInfixExpression)
+	par.registerInfix(token.OptionalChain, par.parseOptionalChain)
 
 	return par
 }
@@ -93,6 +118,12 @@ func (par *Parser) populateCurAndPeekToken() {
 func (par *Parser) nextToken() {
 	par.curToken = par.peekToken
 	par.peekToken = par.lex.NextToken()
+
+	for par.peekToken.Type == token.Comment {
+		trailing := par.curToken.Type != "" && par.curToken.Line == par.peekToken.Line
+		par.comments = append(par.comments, &ast.Comment{Token: par.peekToken, Trailing: trailing})
+		par.peekToken = par.lex.NextToken()
+	}
 }
 
 func (par *Parser) ParseProgram() *ast.Program {
@@ -105,6 +136,7 @@ func (par *Parser) ParseProgram() *ast.Program {
 		par.nextToken()
 	}
 
+	program.Comments = par.comments
 	return program
 }
 
@@ -130,6 +162,11 @@ func (par *Parser) Errors() []string {
 	return par.errors
 }
 
+// Warnings reports code which is valid, but most likely not intended.
+func (par *Parser) Warnings() []string {
+	return par.warnings
+}
+
 func (par *Parser) peekError(tokenType token.TokenType) {
 	msg := fmt.Sprintf("expected next token to be %s, got %s instead", tokenType, par.peekToken.Type)
 	par.errors = append(par.errors, msg)
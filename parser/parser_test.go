@@ -122,6 +122,110 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"a[1:2] + b[:c * 2] + d[::-1] + e[f:]",
+			"((((a[1:2]) + (b[:(c * 2)])) + (d[::(-1)])) + (e[f:]))",
+		},
+		{
+			"a[:]",
+			"(a[:])",
+		},
+		{
+			"a.b.c(d) * e.f",
+			"(((a.b).c)(d) * (e.f))",
+		},
+		{
+			`import "lib" + a.b`,
+			"(import lib + (a.b))",
+		},
+		{
+			`(import "lib").add(1, 2)`,
+			"(import lib.add)(1, 2)",
+		},
+		{
+			"a |> f",
+			"f(a)",
+		},
+		{
+			"a |> f(b) |> g",
+			"g(f(a, b))",
+		},
+		{
+			"a + b |> f == c * d |> g",
+			"(f((a + b)) == g((c * d)))",
+		},
+		{
+			"xs |> map(x => x * 2)",
+			"map(xs, fn(x) (x * 2))",
+		},
+		{
+			"(a, b) => a + b",
+			"fn(a, b) (a + b)",
+		},
+		{
+			"x => y => x + y",
+			"fn(x) fn(y) (x + y)",
+		},
+		{
+			"() => 1",
+			"fn() 1",
+		},
+		{
+			"(x) => x",
+			"fn(x) x",
+		},
+		{
+			"(first, ...others) => others",
+			"fn(first, ...others) others",
+		},
+		{
+			"f((a, b) => a, c)",
+			"f(fn(a, b) a, c)",
+		},
+		{
+			"a ? b : c",
+			"(a ? b : c)",
+		},
+		{
+			"a < b ? a + 1 : b * 2",
+			"((a < b) ? (a + 1) : (b * 2))",
+		},
+		{
+			"a ? b : c ? d : e",
+			"(a ? b : (c ? d : e))",
+		},
+		{
+			"a ? b ? c : d : e",
+			"(a ? (b ? c : d) : e)",
+		},
+		{
+			"a ?? b ?? c",
+			"((a ?? b) ?? c)",
+		},
+		{
+			"a ?? b == c",
+			"(a ?? (b == c))",
+		},
+		{
+			"a ?? b ? c : d",
+			"((a ?? b) ? c : d)",
+		},
+		{
+			"a?.b.c",
+			"((a?.b).c)",
+		},
+		{
+			"a?.[0]?.[1:2]",
+			"((a?.[0])?.[1:2])",
+		},
+		{
+			"f(a?.b ?? 1, x ? 2 : 3)",
+			"f(((a?.b) ?? 1), (x ? 2 : 3))",
+		},
+		{
+			"{\"a\": x ? 1 : 2}",
+			"{a: (x ? 1 : 2)}",
+		},
 	}
 
 	for _, test := range tests {
@@ -130,12 +234,59 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 	}
 }
 
+func TestParseLambdaErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(a, 1) => a", "invalid lambda parameter: 1"},
+		{"1 => 2", "invalid lambda parameter: 1"},
+		{"(a, b)", "expected next token to be =>, got EOF instead"},
+		{"()", "expected next token to be =>, got EOF instead"},
+	}
+
+	for _, test := range tests {
+		par := parser.NewParser(lexer.NewLexer(test.input))
+		par.ParseProgram()
+		assert.Contains(t, par.Errors(), test.expected, test.input)
+	}
+}
+
+func TestParseConditionalErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a ? b", "expected next token to be :, got EOF instead"},
+		{"a?.1", "expected next token to be IDENT, got INT instead"},
+	}
+
+	for _, test := range tests {
+		par := parser.NewParser(lexer.NewLexer(test.input))
+		par.ParseProgram()
+		assert.Contains(t, par.Errors(), test.expected, test.input)
+	}
+}
+
 func TestParseMissingSemicolon(t *testing.T) {
 	input := "let x = 1 * 2 * 3 * 4 * 5"
 	program := testParse(t, input)
 	assert.Equal(t, "let x = ((((1 * 2) * 3) * 4) * 5);", program.String())
 }
 
+func TestParseComments(t *testing.T) {
+	input := "// one\nlet x = 1 / 2; // two\n//three"
+	program := testParse(t, input)
+	assert.Equal(t, "let x = (1 / 2);", program.String())
+	assert.Len(t, program.Comments, 3)
+	assert.Equal(t, "one", program.Comments[0].Text())
+	assert.Equal(t, "2:16", program.Comments[1].Pos().String())
+	assert.False(t, program.Comments[0].Trailing)
+	assert.True(t, program.Comments[1].Trailing)
+	assert.False(t, program.Comments[2].Trailing)
+	assert.Equal(t, "three", program.Comments[2].Text())
+}
+
 func testParse(t *testing.T, input string) *ast.Program {
 	lex := lexer.NewLexer(input)
 	par := parser.NewParser(lex)
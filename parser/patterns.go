@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+func (par *Parser) parsePattern() ast.Pattern {
+	switch par.curToken.Type {
+	case token.Ident:
+		return par.parseTypePattern(&ast.Identifier{Token: par.curToken, Value: par.curToken.Literal})
+	case token.Int:
+		intLiteral, _ := par.parseIntegerLiteral().(*ast.IntegerLiteral)
+		return patternOrNil(intLiteral)
+	case token.Minus:
+		return par.parseNegativeIntegerPattern()
+	case token.String:
+		return par.parseStringLiteral().(*ast.StringLiteral)
+	case token.True, token.False:
+		return par.parseBooleanLiteral().(*ast.BooleanLiteral)
+	case token.LBracket:
+		return par.parseArrayPattern()
+	case token.LBrace:
+		return par.parseHashPattern()
+	default:
+		msg := fmt.Sprintf("expected a pattern, got %s instead", par.curToken.Type)
+		par.errors = append(par.errors, msg)
+		return nil
+	}
+}
+
+// Prevents a nil literal from becoming a non-nil interface value
+func patternOrNil(intLiteral *ast.IntegerLiteral) ast.Pattern {
+	if intLiteral == nil {
+		return nil
+	}
+
+	return intLiteral
+}
+
+func (par *Parser) parseNegativeIntegerPattern() ast.Pattern {
+	minus := par.curToken
+
+	if !par.expectPeek(token.Int) {
+		return nil
+	}
+
+	par.curToken = token.Token{Type: token.Int, Literal: "-" + par.curToken.Literal, Position: minus.Position}
+	intLiteral, _ := par.parseIntegerLiteral().(*ast.IntegerLiteral)
+	return patternOrNil(intLiteral)
+}
+
+// Parses an optional type following an identifier
+func (par *Parser) parseTypePattern(target *ast.Identifier) ast.Pattern {
+	if !par.peekTokenIs(token.Colon) {
+		return target
+	}
+
+	par.nextToken()
+	pattern := &ast.TypePattern{Token: par.curToken, Target: target}
+	var ok bool
+
+	if pattern.Type, ok = par.parseType(); !ok {
+		return nil
+	}
+
+	return pattern
+}
+
+// Parses the name of a type following a colon
+func (par *Parser) parseType() (string, bool) {
+	par.nextToken()
+
+	if !ast.IsType(par.curToken.Literal) || par.curTokenIs(token.String) {
+		msg := fmt.Sprintf("unknown type: %s", par.curToken.Literal)
+		par.errors = append(par.errors, msg)
+		return "", false
+	}
+
+	return par.curToken.Literal, true
+}
+
+// Parses an optional default value following a pattern
+func (par *Parser) parseDefaultPattern(target ast.Pattern) ast.Pattern {
+	if !par.peekTokenIs(token.Assign) {
+		return target
+	}
+
+	par.nextToken()
+	pattern := &ast.DefaultPattern{Token: par.curToken, Target: target}
+	par.nextToken()
+	pattern.Default = par.parseExpression(Lowest)
+	return pattern
+}
+
+func (par *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: par.curToken}
+
+	for !par.peekTokenIs(token.RBracket) {
+		par.nextToken()
+
+		if par.curTokenIs(token.Ellipsis) {
+			if !par.expectPeek(token.Ident) {
+				return nil
+			}
+
+			pattern.Rest = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+			break
+		}
+
+		element := par.parsePattern()
+
+		if element == nil {
+			return nil
+		}
+
+		pattern.Elements = append(pattern.Elements, par.parseDefaultPattern(element))
+
+		if !par.peekTokenIs(token.RBracket) && !par.expectPeek(token.Comma) {
+			return nil
+		}
+	}
+
+	if !par.expectPeek(token.RBracket) {
+		return nil
+	}
+
+	return pattern
+}
+
+func (par *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: par.curToken}
+
+	for !par.peekTokenIs(token.RBrace) {
+		par.nextToken()
+
+		if !par.curTokenIs(token.Ident) && !par.curTokenIs(token.String) {
+			msg := fmt.Sprintf("expected a key, got %s instead", par.curToken.Type)
+			par.errors = append(par.errors, msg)
+			return nil
+		}
+
+		entry := &ast.HashPatternEntry{
+			Key: &ast.StringLiteral{Token: par.curToken, Value: par.curToken.Literal},
+		}
+
+		if par.peekTokenIs(token.Colon) {
+			par.nextToken()
+			par.nextToken()
+			entry.Value = par.parsePattern()
+
+			if entry.Value == nil {
+				return nil
+			}
+		} else if par.curTokenIs(token.Ident) {
+			entry.Value = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+		} else {
+			par.peekError(token.Colon)
+			return nil
+		}
+
+		entry.Value = par.parseDefaultPattern(entry.Value)
+		pattern.Entries = append(pattern.Entries, entry)
+
+		if !par.peekTokenIs(token.RBrace) && !par.expectPeek(token.Comma) {
+			return nil
+		}
+	}
+
+	if !par.expectPeek(token.RBrace) {
+		return nil
+	}
+
+	return pattern
+}
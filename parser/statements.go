@@ -11,6 +11,16 @@ func (par *Parser) parseStatement() ast.Statement {
 		return par.parseLetStatement()
 	case token.Return:
 		return par.parseReturnStatement()
+	case token.Throw:
+		return par.parseThrowStatement()
+	case token.Export:
+		return par.parseExportStatement()
+	case token.Function:
+		if par.peekTokenIs(token.Ident) {
+			return par.parseFunctionDeclaration()
+		}
+
+		return par.parseExpressionStatement()
 	default:
 		return par.parseExpressionStatement()
 	}
@@ -19,11 +29,27 @@ func (par *Parser) parseStatement() ast.Statement {
 func (par *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: par.curToken}
 
-	if !par.expectPeek(token.Ident) {
+	if par.peekTokenIs(token.LBracket) || par.peekTokenIs(token.LBrace) {
+		par.nextToken()
+		stmt.Pattern = par.parsePattern()
+
+		if stmt.Pattern == nil {
+			return nil
+		}
+	} else if par.expectPeek(token.Ident) {
+		stmt.Name = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+	} else {
 		return nil
 	}
 
-	stmt.Name = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+	if stmt.Name != nil && par.peekTokenIs(token.Colon) {
+		var ok bool
+		par.nextToken()
+
+		if stmt.Type, ok = par.parseType(); !ok {
+			return nil
+		}
+	}
 
 	if !par.expectPeek(token.Assign) {
 		return nil
@@ -39,6 +65,39 @@ func (par *Parser) parseLetStatement() *ast.LetStatement {
 	return stmt
 }
 
+func (par *Parser) parseFunctionDeclaration() ast.Statement {
+	stmt := &ast.FunctionDeclaration{Token: par.curToken}
+	stmt.Function = &ast.FunctionLiteral{Token: par.curToken}
+	par.nextToken()
+	stmt.Function.Name = &ast.Identifier{Token: par.curToken, Value: par.curToken.Literal}
+
+	if !par.parseFunction(stmt.Function) {
+		return nil
+	}
+
+	if par.peekTokenIs(token.Semicolon) {
+		par.nextToken()
+	}
+
+	return stmt
+}
+
+func (par *Parser) parseExportStatement() ast.Statement {
+	stmt := &ast.ExportStatement{Token: par.curToken}
+
+	if !par.expectPeek(token.Let) {
+		return nil
+	}
+
+	stmt.Statement = par.parseLetStatement()
+
+	if stmt.Statement == nil {
+		return nil
+	}
+
+	return stmt
+}
+
 func (par *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: par.curToken}
 	par.nextToken()
@@ -51,6 +110,18 @@ func (par *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+func (par *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: par.curToken}
+	par.nextToken()
+	stmt.Value = par.parseExpression(Lowest)
+
+	if par.peekTokenIs(token.Semicolon) {
+		par.nextToken()
+	}
+
+	return stmt
+}
+
 func (par *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: par.curToken}
 	stmt.Expression = par.parseExpression(Lowest)
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/parser"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,6 +30,111 @@ func TestParseLetStatements(t *testing.T) {
 	}
 }
 
+func TestParseAnnotatedLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		ident    string
+		typeName string
+		expected string
+	}{
+		{"let x: int = 5;", "x", "int", "let x: int = 5;"},
+		{"let f: fn = fn(a) { a };", "f", "fn", "let f: fn = fn(a) a;"},
+		{"let y = 10;", "y", "", "let y = 10;"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		assert.True(t, ok)
+		testLetStatememt(t, stmt, test.ident)
+		assert.Equal(t, test.typeName, stmt.Type)
+		assert.Equal(t, test.expected, stmt.String())
+	}
+
+	par := parser.NewParser(lexer.NewLexer("let x: number = 5;"))
+	par.ParseProgram()
+	assert.Contains(t, par.Errors(), "unknown type: number")
+}
+
+func TestParseDestructuringLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		names    []string
+	}{
+		{"let [a, b] = arr;", "let [a, b] = arr;", []string{"a", "b"}},
+		{"let [a, ...rest] = arr", "let [a, ...rest] = arr;", []string{"a", "rest"}},
+		{"let [...rest] = arr", "let [...rest] = arr;", []string{"rest"}},
+		{"let [] = arr", "let [] = arr;", []string{}},
+		{"let [a, b = 1 + 2] = arr", "let [a, b = (1 + 2)] = arr;", []string{"a", "b"}},
+		{"let [[a, b], c] = arr", "let [[a, b], c] = arr;", []string{"a", "b", "c"}},
+		{"let {name, age} = person", "let {name, age} = person;", []string{"name", "age"}},
+		{"let {name: n, age = 30} = person", "let {name: n, age = 30} = person;", []string{"n", "age"}},
+		{`let {"first name": first} = person`, "let {first name: first} = person;", []string{"first"}},
+		{"let {address: {city}, tags: [tag]} = person", "let {address: {city}, tags: [tag]} = person;", []string{"city", "tag"}},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		assert.Len(t, program.Statements, 1)
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		assert.True(t, ok)
+		assert.Nil(t, stmt.Name)
+		assert.NotNil(t, stmt.Pattern)
+		assert.Equal(t, test.expected, stmt.String())
+		assert.Equal(t, test.names, stmt.Names())
+	}
+}
+
+func TestParseDestructuringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, (b)] = arr", "expected a pattern, got ( instead"},
+		{"let [...rest, a] = arr", "expected next token to be ], got , instead"},
+		{"let [a b] = arr", "expected next token to be ,, got IDENT instead"},
+		{"let {1: a} = person", "expected a key, got INT instead"},
+		{`let {"name"} = person`, "expected next token to be :, got } instead"},
+	}
+
+	for _, test := range tests {
+		par := parser.NewParser(lexer.NewLexer(test.input))
+		par.ParseProgram()
+		assert.Contains(t, par.Errors(), test.expected, test.input)
+	}
+}
+
+func TestParseFunctionDeclarations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		name     string
+	}{
+		{"fn add(x, y) { x + y }", "fn add(x, y) (x + y)", "add"},
+		{"fn nothing() { };", "fn nothing() ", "nothing"},
+		{"fn opts(x = 1, ...rest) { x }", "fn opts(x = 1, ...rest) x", "opts"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		assert.Len(t, program.Statements, 1)
+		stmt, ok := program.Statements[0].(*ast.FunctionDeclaration)
+		assert.True(t, ok)
+		assert.Equal(t, "fn", stmt.TokenLiteral())
+		assert.Equal(t, test.name, stmt.Function.Name.Value)
+		assert.Equal(t, test.expected, stmt.String())
+	}
+
+	program := testParse(t, "fn(x) { x }(1)")
+	_, ok := program.Statements[0].(*ast.ExpressionStatement)
+	assert.True(t, ok)
+
+	par := parser.NewParser(lexer.NewLexer("let f = fn g() { 1 }"))
+	par.ParseProgram()
+	assert.Contains(t, par.Errors(), "expected next token to be (, got IDENT instead")
+}
+
 func TestParseReturnStatements(t *testing.T) {
 	tests := []struct {
 		input string
@@ -48,6 +155,42 @@ func TestParseReturnStatements(t *testing.T) {
 	}
 }
 
+func TestParseThrowStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`throw "oops";`, `throw oops;`},
+		{"throw x + 1", "throw (x + 1);"},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		assert.Len(t, program.Statements, 1)
+		stmt, ok := program.Statements[0].(*ast.ThrowStatement)
+		assert.True(t, ok)
+		assert.Equal(t, "throw", stmt.TokenLiteral())
+		assert.Equal(t, test.expected, stmt.String())
+	}
+}
+
+func TestParseExportStatements(t *testing.T) {
+	program := testParse(t, "export let x = 5;")
+	assert.Len(t, program.Statements, 1)
+	stmt, ok := program.Statements[0].(*ast.ExportStatement)
+	assert.True(t, ok)
+	testLetStatememt(t, stmt.Statement, "x")
+	testLiteral(t, stmt.Statement.Value, 5)
+	assert.Equal(t, "export let x = 5;", stmt.String())
+}
+
+func TestParseExportDestructuringStatement(t *testing.T) {
+	program := testParse(t, "export let {add, sub} = import \"math\";")
+	stmt, ok := program.Statements[0].(*ast.ExportStatement)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"add", "sub"}, stmt.Statement.Names())
+}
+
 func testLetStatememt(t *testing.T, stmt ast.Statement, name string) {
 	assert.Equal(t, "let", stmt.TokenLiteral())
 	letStmt, ok := stmt.(*ast.LetStatement)
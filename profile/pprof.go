@@ -0,0 +1,222 @@
+package profile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+)
+
+// Field numbers of the messages in profile.proto, see
+// https://github.com/google/pprof/blob/main/proto/profile.proto
+const (
+	profileSampleType    = 1
+	profileSample        = 2
+	profileLocation      = 4
+	profileFunction      = 5
+	profileStringTable   = 6
+	profileTimeNanos     = 9
+	profileDurationNanos = 10
+	profilePeriodType    = 11
+	profilePeriod        = 12
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+
+	locationID   = 1
+	locationLine = 4
+
+	lineFunctionID = 1
+	lineLine       = 2
+
+	functionID         = 1
+	functionName       = 2
+	functionSystemName = 3
+	functionFilename   = 4
+)
+
+// WritePprof writes the profile as a gzipped protocol buffer, which can be
+// read by go tool pprof. Each sample has the values calls, statements and
+// time.
+func (prof *Profile) WritePprof(w io.Writer) error {
+	enc := newPprofEncoder()
+	var out buffer
+
+	for _, valueType := range [][2]string{{"calls", "count"}, {"statements", "count"}, {"time", "nanoseconds"}} {
+		out.bytesField(profileSampleType, enc.valueType(valueType[0], valueType[1]))
+	}
+
+	for _, sample := range prof.Samples {
+		ids := make([]uint64, len(sample.Stack))
+
+		for i, frame := range sample.Stack {
+			ids[i] = enc.location(frame)
+		}
+
+		var msg buffer
+		msg.packed(sampleLocationID, ids)
+		msg.packed(sampleValue, []uint64{uint64(sample.Calls), uint64(sample.Statements), uint64(sample.Time.Nanoseconds())})
+		out.bytesField(profileSample, msg.Bytes())
+	}
+
+	for _, location := range enc.locations {
+		out.bytesField(profileLocation, location)
+	}
+
+	for _, function := range enc.functions {
+		out.bytesField(profileFunction, function)
+	}
+
+	periodType := enc.valueType("time", "nanoseconds")
+
+	for _, str := range enc.strings {
+		out.bytesField(profileStringTable, []byte(str))
+	}
+
+	out.uint64Field(profileTimeNanos, uint64(prof.Start.UnixNano()))
+	out.uint64Field(profileDurationNanos, uint64(prof.Duration.Nanoseconds()))
+	out.bytesField(profilePeriodType, periodType)
+	out.uint64Field(profilePeriod, 1)
+
+	zw := gzip.NewWriter(w)
+
+	if _, err := zw.Write(out.Bytes()); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+type functionKey struct {
+	name string
+	file string
+}
+
+// Collects the functions, locations and strings samples refer to by their
+// index
+type pprofEncoder struct {
+	strings     []string
+	stringIDs   map[string]uint64
+	functions   [][]byte
+	functionIDs map[functionKey]uint64
+	locations   [][]byte
+	locationIDs map[Frame]uint64
+}
+
+func newPprofEncoder() *pprofEncoder {
+	return &pprofEncoder{
+		strings:     []string{""},
+		stringIDs:   map[string]uint64{"": 0},
+		functionIDs: make(map[functionKey]uint64),
+		locationIDs: make(map[Frame]uint64),
+	}
+}
+
+func (enc *pprofEncoder) str(value string) uint64 {
+	if id, ok := enc.stringIDs[value]; ok {
+		return id
+	}
+
+	id := uint64(len(enc.strings))
+	enc.strings = append(enc.strings, value)
+	enc.stringIDs[value] = id
+	return id
+}
+
+func (enc *pprofEncoder) valueType(typeName, unit string) []byte {
+	var msg buffer
+	msg.uint64Field(valueTypeType, enc.str(typeName))
+	msg.uint64Field(valueTypeUnit, enc.str(unit))
+	return msg.Bytes()
+}
+
+func (enc *pprofEncoder) function(name, file string) uint64 {
+	key := functionKey{name: name, file: file}
+
+	if id, ok := enc.functionIDs[key]; ok {
+		return id
+	}
+
+	id := uint64(len(enc.functions) + 1)
+	var msg buffer
+
+	// pprof drops anything in angle brackets as template arguments
+	if strings.HasPrefix(name, "<") && strings.HasSuffix(name, ">") {
+		name = "[" + name[1:len(name)-1] + "]"
+	}
+
+	msg.uint64Field(functionID, id)
+	msg.uint64Field(functionName, enc.str(name))
+	msg.uint64Field(functionSystemName, enc.str(name))
+	msg.uint64Field(functionFilename, enc.str(file))
+	enc.functions = append(enc.functions, msg.Bytes())
+	enc.functionIDs[key] = id
+	return id
+}
+
+func (enc *pprofEncoder) location(frame Frame) uint64 {
+	if id, ok := enc.locationIDs[frame]; ok {
+		return id
+	}
+
+	id := uint64(len(enc.locations) + 1)
+	var line buffer
+	line.uint64Field(lineFunctionID, enc.function(frame.Function, frame.File))
+	line.uint64Field(lineLine, uint64(frame.Line))
+	var msg buffer
+	msg.uint64Field(locationID, id)
+	msg.bytesField(locationLine, line.Bytes())
+	enc.locations = append(enc.locations, msg.Bytes())
+	enc.locationIDs[frame] = id
+	return id
+}
+
+// buffer encodes protocol buffer fields. Fields with the value zero are left
+// out, as that is their default.
+type buffer struct {
+	bytes.Buffer
+}
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func (buf *buffer) varint(value uint64) {
+	for value >= 0x80 {
+		buf.WriteByte(byte(value) | 0x80)
+		value >>= 7
+	}
+
+	buf.WriteByte(byte(value))
+}
+
+func (buf *buffer) key(field int, wireType int) {
+	buf.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (buf *buffer) uint64Field(field int, value uint64) {
+	if value != 0 {
+		buf.key(field, wireVarint)
+		buf.varint(value)
+	}
+}
+
+func (buf *buffer) bytesField(field int, value []byte) {
+	buf.key(field, wireBytes)
+	buf.varint(uint64(len(value)))
+	buf.Write(value)
+}
+
+func (buf *buffer) packed(field int, values []uint64) {
+	var msg buffer
+
+	for _, value := range values {
+		msg.varint(value)
+	}
+
+	buf.bytesField(field, msg.Bytes())
+}
@@ -0,0 +1,30 @@
+// Package profile describes where a Monkey program spends its time, as
+// recorded by the evaluator, and writes it in the pprof format or as a text
+// summary.
+package profile
+
+import "time"
+
+// Frame is a source line of a Monkey function. Code outside of functions runs
+// in the frames <main> and <module>.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Sample holds the values recorded while the program was at the given stack,
+// which starts with the innermost frame. Calls counts how often the innermost
+// frame was entered.
+type Sample struct {
+	Stack      []Frame
+	Calls      int64
+	Statements int64
+	Time       time.Duration
+}
+
+type Profile struct {
+	Start    time.Time
+	Duration time.Duration
+	Samples  []*Sample
+}
@@ -0,0 +1,67 @@
+package profile_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/henningstorck/monkey-interpreter/profile"
+	"github.com/stretchr/testify/assert"
+)
+
+func testProfile() *profile.Profile {
+	main := profile.Frame{Function: "<main>", File: "/tmp/fib.monkey", Line: 4}
+	fib := profile.Frame{Function: "fib", File: "/tmp/fib.monkey", Line: 2}
+
+	return &profile.Profile{
+		Start:    time.Unix(0, 0),
+		Duration: 10 * time.Millisecond,
+		Samples: []*profile.Sample{
+			{Stack: []profile.Frame{main}, Statements: 1, Time: 2 * time.Millisecond},
+			{Stack: []profile.Frame{fib, main}, Calls: 1, Statements: 1, Time: 3 * time.Millisecond},
+			{Stack: []profile.Frame{fib, fib, main}, Calls: 2, Statements: 2, Time: 5 * time.Millisecond},
+		},
+	}
+}
+
+func TestWriteTop(t *testing.T) {
+	var out strings.Builder
+	testProfile().WriteTop(&out, 10)
+
+	expected := `Total: 10ms
+      flat  flat%        cum   cum%    calls  function
+       8ms  80.0%        8ms  80.0%        3  fib
+       2ms  20.0%       10ms 100.0%        0  <main>
+
+      flat  flat% statements  line
+       8ms  80.0%          3  fib.monkey:2 (fib)
+       2ms  20.0%          1  fib.monkey:4 (<main>)
+`
+
+	assert.Equal(t, expected, out.String())
+}
+
+func TestWriteTopLimit(t *testing.T) {
+	var out strings.Builder
+	testProfile().WriteTop(&out, 1)
+	assert.Equal(t, 6, strings.Count(out.String(), "\n"))
+}
+
+func TestWritePprof(t *testing.T) {
+	var out bytes.Buffer
+	assert.Nil(t, testProfile().WritePprof(&out))
+
+	reader, err := gzip.NewReader(&out)
+	assert.Nil(t, err)
+	data, err := io.ReadAll(reader)
+	assert.Nil(t, err)
+
+	for _, str := range []string{"calls", "statements", "time", "nanoseconds", "fib", "[main]", "/tmp/fib.monkey"} {
+		assert.True(t, bytes.Contains(data, []byte(str)), str)
+	}
+
+	assert.False(t, bytes.Contains(data, []byte("<main>")))
+}
@@ -0,0 +1,131 @@
+package profile
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type functionStats struct {
+	name  string
+	flat  time.Duration
+	cum   time.Duration
+	calls int64
+}
+
+type lineStats struct {
+	frame      Frame
+	flat       time.Duration
+	statements int64
+}
+
+// WriteTop writes the n functions and source lines the most time was spent in,
+// like the top command of go tool pprof. Flat time is spent in a function
+// itself, cumulative time includes the functions it calls.
+func (prof *Profile) WriteTop(w io.Writer, n int) {
+	functions := map[string]*functionStats{}
+	lines := map[Frame]*lineStats{}
+	total := time.Duration(0)
+
+	for _, sample := range prof.Samples {
+		if len(sample.Stack) == 0 {
+			continue
+		}
+
+		total += sample.Time
+		leaf := sample.Stack[0]
+		leafStats := function(functions, leaf.Function)
+		leafStats.flat += sample.Time
+		leafStats.calls += sample.Calls
+		seen := map[string]bool{}
+
+		// Recursive functions are on the stack several times, but their time
+		// only counts once
+		for _, frame := range sample.Stack {
+			if !seen[frame.Function] {
+				seen[frame.Function] = true
+				function(functions, frame.Function).cum += sample.Time
+			}
+		}
+
+		if lines[leaf] == nil {
+			lines[leaf] = &lineStats{frame: leaf}
+		}
+
+		lines[leaf].flat += sample.Time
+		lines[leaf].statements += sample.Statements
+	}
+
+	sortedFunctions := make([]*functionStats, 0, len(functions))
+
+	for _, stats := range functions {
+		sortedFunctions = append(sortedFunctions, stats)
+	}
+
+	sort.Slice(sortedFunctions, func(i, j int) bool {
+		if sortedFunctions[i].flat != sortedFunctions[j].flat {
+			return sortedFunctions[i].flat > sortedFunctions[j].flat
+		}
+
+		return sortedFunctions[i].name < sortedFunctions[j].name
+	})
+
+	sortedLines := make([]*lineStats, 0, len(lines))
+
+	for _, stats := range lines {
+		sortedLines = append(sortedLines, stats)
+	}
+
+	sort.Slice(sortedLines, func(i, j int) bool {
+		if sortedLines[i].flat != sortedLines[j].flat {
+			return sortedLines[i].flat > sortedLines[j].flat
+		}
+
+		left, right := sortedLines[i].frame, sortedLines[j].frame
+		return left.File < right.File || (left.File == right.File && left.Line < right.Line)
+	})
+
+	fmt.Fprintf(w, "Total: %v\n", total)
+	fmt.Fprintf(w, "%10s %6s %10s %6s %8s  %s\n", "flat", "flat%", "cum", "cum%", "calls", "function")
+
+	for i, stats := range sortedFunctions {
+		if i == n {
+			break
+		}
+
+		fmt.Fprintf(w, "%10v %6s %10v %6s %8d  %s\n", round(stats.flat), percent(stats.flat, total), round(stats.cum), percent(stats.cum, total), stats.calls, stats.name)
+	}
+
+	fmt.Fprintf(w, "\n%10s %6s %10s  %s\n", "flat", "flat%", "statements", "line")
+
+	for i, stats := range sortedLines {
+		if i == n {
+			break
+		}
+
+		location := fmt.Sprintf("%s:%d", filepath.Base(stats.frame.File), stats.frame.Line)
+		fmt.Fprintf(w, "%10v %6s %10d  %s (%s)\n", round(stats.flat), percent(stats.flat, total), stats.statements, location, stats.frame.Function)
+	}
+}
+
+func function(functions map[string]*functionStats, name string) *functionStats {
+	if functions[name] == nil {
+		functions[name] = &functionStats{name: name}
+	}
+
+	return functions[name]
+}
+
+func percent(value, total time.Duration) string {
+	if total == 0 {
+		return "0.0%"
+	}
+
+	return fmt.Sprintf("%.1f%%", float64(value)*100/float64(total))
+}
+
+func round(duration time.Duration) time.Duration {
+	return duration.Round(time.Microsecond)
+}
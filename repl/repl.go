@@ -9,6 +9,7 @@ import (
 	"github.com/henningstorck/monkey-interpreter/lexer"
 	"github.com/henningstorck/monkey-interpreter/object"
 	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/henningstorck/monkey-interpreter/resolver"
 )
 
 const prompt = ">> "
@@ -48,9 +49,17 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
+		for _, msg := range par.Warnings() {
+			io.WriteString(out, "warning: "+msg+"\n")
+		}
+
+		resolver.Resolve(program)
 		evaluated := evaluator.Eval(program, env)
 
-		if evaluated != nil {
+		if errObj, ok := evaluated.(*object.Error); ok {
+			io.WriteString(out, errObj.Traceback())
+			io.WriteString(out, "\n")
+		} else if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
 		}
@@ -0,0 +1,164 @@
+// Package resolver binds identifiers to the slots of the frames that hold
+// them, so the evaluator does not need to look local bindings up by name.
+//
+// Every function body, match arm and catch block is a scope, just like the
+// environments the evaluator creates for them. All names declared anywhere in a
+// scope get a slot when entering it, so a closure refers to a binding even if
+// it is declared after the closure. Identifiers that are not declared in any
+// enclosing scope, like globals and builtins, stay unresolved.
+package resolver
+
+import "github.com/henningstorck/monkey-interpreter/ast"
+
+type scope struct {
+	names []string
+	slots map[string]int
+}
+
+func (sc *scope) declare(name string) {
+	if _, ok := sc.slots[name]; !ok {
+		sc.slots[name] = len(sc.names)
+		sc.names = append(sc.names, name)
+	}
+}
+
+type resolver struct {
+	scopes []*scope
+}
+
+// Resolve annotates the identifiers of the tree in place. Resolving a tree again
+// gives the same result.
+func Resolve(node ast.Node) {
+	res := &resolver{}
+	res.resolve(node)
+}
+
+func (res *resolver) resolve(node ast.Node) {
+	ast.Walk(node, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Identifier:
+			res.resolveIdentifier(node)
+		case *ast.FunctionLiteral:
+			res.resolveFunction(node)
+			return false
+		case *ast.MatchExpression:
+			res.resolve(node.Subject)
+
+			for _, arm := range node.Arms {
+				res.resolveArm(arm)
+			}
+
+			return false
+		case *ast.TryExpression:
+			res.resolve(node.Block)
+
+			if node.Catch != nil {
+				res.resolveCatch(node)
+			}
+
+			if node.Finally != nil {
+				res.resolve(node.Finally)
+			}
+
+			return false
+		}
+
+		return true
+	})
+}
+
+func (res *resolver) resolveIdentifier(ident *ast.Identifier) {
+	ident.Resolved, ident.Depth, ident.Slot = false, 0, 0
+
+	for i := len(res.scopes) - 1; i >= 0; i-- {
+		if slot, ok := res.scopes[i].slots[ident.Value]; ok {
+			ident.Resolved, ident.Depth, ident.Slot = true, len(res.scopes)-1-i, slot
+			return
+		}
+	}
+}
+
+// The name of a function declaration belongs to the enclosing scope
+func (res *resolver) resolveFunction(fnLiteral *ast.FunctionLiteral) {
+	if fnLiteral.Name != nil {
+		res.resolveIdentifier(fnLiteral.Name)
+	}
+
+	sc := res.push()
+
+	for _, param := range fnLiteral.Parameters {
+		sc.declare(param.Value)
+	}
+
+	if fnLiteral.Rest != nil {
+		sc.declare(fnLiteral.Rest.Value)
+	}
+
+	declare(sc, fnLiteral.Body)
+
+	for i, param := range fnLiteral.Parameters {
+		res.resolveIdentifier(param)
+
+		if i < len(fnLiteral.Defaults) && fnLiteral.Defaults[i] != nil {
+			res.resolve(fnLiteral.Defaults[i])
+		}
+	}
+
+	if fnLiteral.Rest != nil {
+		res.resolveIdentifier(fnLiteral.Rest)
+	}
+
+	res.resolve(fnLiteral.Body)
+	fnLiteral.Locals = res.pop()
+}
+
+func (res *resolver) resolveArm(arm *ast.MatchArm) {
+	sc := res.push()
+
+	for _, name := range ast.PatternNames(arm.Pattern) {
+		sc.declare(name)
+	}
+
+	if arm.Guard != nil {
+		declare(sc, arm.Guard)
+	}
+
+	declare(sc, arm.Body)
+	res.resolve(arm.Pattern)
+
+	if arm.Guard != nil {
+		res.resolve(arm.Guard)
+	}
+
+	res.resolve(arm.Body)
+	arm.Locals = res.pop()
+}
+
+func (res *resolver) resolveCatch(tryExp *ast.TryExpression) {
+	sc := res.push()
+	sc.declare(tryExp.Parameter.Value)
+	declare(sc, tryExp.Catch)
+	res.resolveIdentifier(tryExp.Parameter)
+	res.resolve(tryExp.Catch)
+	tryExp.CatchLocals = res.pop()
+}
+
+func (res *resolver) push() *scope {
+	sc := &scope{names: []string{}, slots: make(map[string]int)}
+	res.scopes = append(res.scopes, sc)
+	return sc
+}
+
+func (res *resolver) pop() []string {
+	sc := res.scopes[len(res.scopes)-1]
+	res.scopes = res.scopes[:len(res.scopes)-1]
+	return sc.names
+}
+
+// Declares the names bound by the node in the scope, without descending into
+// nested scopes
+func declare(sc *scope, node ast.Node) {
+	ast.WalkDeclarations(node, func(ident *ast.Identifier, _ ast.Statement) {
+		sc.declare(ident.Value)
+	})
+}
@@ -0,0 +1,88 @@
+package resolver_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveIdentifiers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let x = 1; x", []string{"x", "x"}},
+		{"fn(x) { x }", []string{"x 0:0", "x 0:0"}},
+		{"fn(x) { let y = x; y }", []string{"x 0:0", "y 0:1", "x 0:0", "y 0:1"}},
+		{"fn(x) { fn(y) { x + y + z } }", []string{"x 0:0", "y 0:0", "x 1:0", "y 0:0", "z"}},
+		{"fn() { let f = fn() { x }; let x = 1 }", []string{"f 0:0", "x 1:1", "x 0:1"}},
+		{"fn(a, b = a) { if (true) { let c = b } }", []string{"a 0:0", "b 0:1", "a 0:0", "c 0:2", "b 0:1"}},
+		{"fn(...xs) { let [a, ...b] = xs }", []string{"xs 0:0", "a 0:1", "b 0:2", "xs 0:0"}},
+		{"fn() { fn g() { g } }", []string{"g 0:0", "g 1:0"}},
+		{"fn(x) { match (x) { [a] if a => a + x } }", []string{"x 0:0", "x 0:0", "a 0:0", "a 0:0", "a 0:0", "x 1:0"}},
+		{"fn() { try { 1 } catch(e) { let m = e; m } }", []string{"e 0:0", "m 0:1", "e 0:0", "m 0:1"}},
+		{"x => y => x + y", []string{"x 0:0", "y 0:0", "x 1:0", "y 0:0"}},
+		{"let f = fn() { len }", []string{"f", "len"}},
+	}
+
+	for _, test := range tests {
+		program := testParse(t, test.input)
+		resolver.Resolve(program)
+		assert.Equal(t, test.expected, identifiers(program), test.input)
+	}
+}
+
+func TestResolveLocals(t *testing.T) {
+	program := testParse(t, "fn(a, b) { let [c, _] = a; fn d() { match (b) { e => e } } try { 1 } catch(f) { let g = f } }")
+	resolver.Resolve(program)
+
+	fnLiteral := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, fnLiteral.Locals)
+
+	fnDecl := fnLiteral.Body.Statements[1].(*ast.FunctionDeclaration)
+	assert.Equal(t, []string{}, fnDecl.Function.Locals)
+
+	matchExp := fnDecl.Function.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.MatchExpression)
+	assert.Equal(t, []string{"e"}, matchExp.Arms[0].Locals)
+
+	tryExp := fnLiteral.Body.Statements[2].(*ast.ExpressionStatement).Expression.(*ast.TryExpression)
+	assert.Equal(t, []string{"f", "g"}, tryExp.CatchLocals)
+}
+
+func TestResolveTwice(t *testing.T) {
+	program := testParse(t, "fn(x) { x }")
+	resolver.Resolve(program)
+	resolver.Resolve(program)
+	assert.Equal(t, []string{"x 0:0", "x 0:0"}, identifiers(program))
+}
+
+func identifiers(program *ast.Program) []string {
+	result := []string{}
+
+	ast.Walk(program, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Identifier); ok {
+			if ident.Resolved {
+				result = append(result, fmt.Sprintf("%s %d:%d", ident.Value, ident.Depth, ident.Slot))
+			} else {
+				result = append(result, ident.Value)
+			}
+		}
+
+		return true
+	})
+
+	return result
+}
+
+func testParse(t *testing.T, input string) *ast.Program {
+	lex := lexer.NewLexer(input)
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+	assert.Empty(t, par.Errors(), input)
+	return program
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/optimizer"
+	"github.com/henningstorck/monkey-interpreter/profile"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+)
+
+func run(args []string) {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	path := flags.String("path", "", "additional directories to resolve imports against")
+	optimize := flags.Bool("optimize", true, "optimize the program before running it")
+	dumpAST := flags.Bool("dump-ast", false, "print the optimized program instead of running it")
+	profilePath := flags.String("profile", "", "write a pprof profile of the run to this file")
+	profileTop := flags.Int("profile-top", 10, "number of functions and lines to summarize when profiling")
+	trace := flags.Bool("trace", false, "write each evaluated node, call and binding to stderr")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		io.WriteString(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	evaluator.SearchPath = append(filepath.SplitList(*path), evaluator.SearchPath...)
+	_, program := parseFile(flags.Arg(0))
+
+	// Traces follow the program as written
+	if (*optimize && !*trace) || *dumpAST {
+		optimizer.Optimize(program)
+	}
+
+	if *dumpAST {
+		for _, stmt := range program.Statements {
+			fmt.Println(stmt.String())
+		}
+
+		return
+	}
+
+	resolver.Resolve(program)
+	env := object.NewEnvironment()
+	evaluator.SetFile(env, flags.Arg(0))
+
+	if *profilePath != "" {
+		evaluator.StartProfile()
+	}
+
+	if *trace {
+		evaluator.SetTrace(os.Stderr)
+	}
+
+	result := evaluator.Eval(program, env)
+
+	if *profilePath != "" {
+		writeProfile(evaluator.StopProfile(), *profilePath, *profileTop)
+	}
+
+	if errObj, ok := result.(*object.Error); ok {
+		fmt.Fprintln(os.Stderr, errObj.Traceback())
+		os.Exit(1)
+	}
+
+	if result != nil {
+		fmt.Println(result.Inspect())
+	}
+}
+
+// Writes the profile and prints a summary of it to stderr
+func writeProfile(prof *profile.Profile, path string, top int) {
+	file, err := os.Create(path)
+
+	if err == nil {
+		err = prof.WritePprof(file)
+
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	prof.WriteTop(os.Stderr, top)
+}
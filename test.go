@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/coverage"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/testrunner"
+)
+
+// Runs the tests in the given files and directories. Tests are not optimized,
+// so coverage refers to the program as written.
+func test(args []string) {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	format := flags.String("format", "text", "report the results as text, tap or junit")
+	cover := flags.Bool("cover", false, "report which statements and branches the tests evaluated")
+	coverList := flags.Bool("cover-list", false, "print the covered files annotated with how often each line was evaluated")
+	coverLCOV := flags.String("cover-lcov", "", "write the coverage in the LCOV format to this file")
+	flags.Parse(args)
+	covering := *cover || *coverList || *coverLCOV != ""
+
+	if *format != "text" && *format != "tap" && *format != "junit" {
+		fmt.Fprintf(os.Stderr, "unknown format: %s\n", *format)
+		os.Exit(2)
+	}
+
+	files, err := testrunner.Find(flags.Args())
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintln(os.Stderr, "no test files found")
+		os.Exit(1)
+	}
+
+	if covering {
+		evaluator.StartCoverage()
+	}
+
+	results := []*testrunner.Result{}
+
+	for _, file := range files {
+		_, program := parseFile(file)
+		results = append(results, testrunner.Run(file, program)...)
+	}
+
+	switch *format {
+	case "tap":
+		testrunner.WriteTAP(os.Stdout, results)
+	case "junit":
+		err = testrunner.WriteJUnit(os.Stdout, results)
+	default:
+		testrunner.WriteText(os.Stdout, results)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// Coverage would break the other formats, so it goes to stderr for them
+	if covering {
+		var out io.Writer = os.Stdout
+
+		if *format != "text" {
+			out = os.Stderr
+		}
+
+		writeCoverage(evaluator.StopCoverage(), out, *coverList, *coverLCOV)
+	}
+
+	for _, result := range results {
+		if !result.Passed() {
+			os.Exit(1)
+		}
+	}
+}
+
+// The test files themselves are left out of the coverage
+func writeCoverage(prof *coverage.Profile, out io.Writer, list bool, lcovPath string) {
+	files := prof.Files[:0]
+
+	for _, file := range prof.Files {
+		if !strings.HasSuffix(file.Path, testrunner.Suffix) {
+			files = append(files, file)
+		}
+	}
+
+	prof.Files = files
+	fmt.Fprintln(out)
+	var err error
+
+	if list {
+		err = prof.WriteListing(out)
+	} else {
+		prof.WriteSummary(out)
+	}
+
+	if err == nil && lcovPath != "" {
+		var file *os.File
+		file, err = os.Create(lcovPath)
+
+		if err == nil {
+			err = prof.WriteLCOV(file)
+
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,194 @@
+package testrunner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteText reports the failed tests with their traceback and the outcome of
+// each file, like go test does.
+func WriteText(w io.Writer, results []*Result) {
+	passed, failed := 0, 0
+
+	for _, file := range groupByFile(results) {
+		fileFailed := false
+		duration := time.Duration(0)
+
+		for _, result := range file {
+			duration += result.Duration
+
+			if result.Passed() {
+				passed++
+				continue
+			}
+
+			failed++
+			fileFailed = true
+
+			if result.Name != "" {
+				fmt.Fprintf(w, "--- FAIL: %s (%s:%s)\n", result.Name, result.File, result.Position)
+			}
+
+			fmt.Fprintf(w, "    %s\n", strings.ReplaceAll(result.Error.Traceback(), "\n", "\n    "))
+		}
+
+		status := "ok  "
+
+		if fileFailed {
+			status = "FAIL"
+		}
+
+		fmt.Fprintf(w, "%s %s\t%.3fs\n", status, file[0].File, duration.Seconds())
+	}
+
+	fmt.Fprintf(w, "%d passed, %d failed\n", passed, failed)
+}
+
+// WriteTAP reports the tests in the Test Anything Protocol, version 13. Failed
+// tests have their position and traceback attached as YAML.
+func WriteTAP(w io.Writer, results []*Result) {
+	fmt.Fprintln(w, "TAP version 13")
+	fmt.Fprintf(w, "1..%d\n", len(results))
+
+	for i, result := range results {
+		status := "ok"
+
+		if !result.Passed() {
+			status = "not ok"
+		}
+
+		fmt.Fprintf(w, "%s %d - %s\n", status, i+1, name(result))
+
+		if !result.Passed() {
+			fmt.Fprintln(w, "  ---")
+
+			if result.Name != "" {
+				fmt.Fprintf(w, "  at: %s:%s\n", result.File, result.Position)
+			}
+
+			fmt.Fprintln(w, "  message: |")
+			fmt.Fprintf(w, "    %s\n", strings.ReplaceAll(result.Error.Traceback(), "\n", "\n    "))
+			fmt.Fprintln(w, "  ...")
+		}
+	}
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// WriteJUnit reports the tests as JUnit XML with a test suite for each file.
+func WriteJUnit(w io.Writer, results []*Result) error {
+	suites := junitSuites{}
+	total := time.Duration(0)
+
+	for _, file := range groupByFile(results) {
+		suite := junitSuite{Name: file[0].File, Tests: len(file)}
+		duration := time.Duration(0)
+
+		for _, result := range file {
+			testCase := junitCase{
+				Name:      result.Name,
+				ClassName: file[0].File,
+				File:      result.File,
+				Line:      result.Position.Line,
+				Time:      seconds(result.Duration),
+			}
+
+			if result.Name == "" {
+				testCase.Name = result.File
+			}
+
+			if !result.Passed() {
+				suite.Failures++
+				testCase.Failure = &junitFailure{
+					Message: strings.SplitN(result.Error.Message, "\n", 2)[0],
+					Type:    result.Error.Kind,
+					Text:    result.Error.Traceback(),
+				}
+			}
+
+			duration += result.Duration
+			suite.Cases = append(suite.Cases, testCase)
+		}
+
+		suite.Time = seconds(duration)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+		total += duration
+	}
+
+	suites.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Results are grouped in the order their files were run
+func groupByFile(results []*Result) [][]*Result {
+	groups := [][]*Result{}
+
+	for i, result := range results {
+		if i == 0 || results[i-1].File != result.File {
+			groups = append(groups, []*Result{})
+		}
+
+		groups[len(groups)-1] = append(groups[len(groups)-1], result)
+	}
+
+	return groups
+}
+
+func name(result *Result) string {
+	if result.Name == "" {
+		return result.File
+	}
+
+	return result.File + ": " + result.Name
+}
+
+func seconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
@@ -0,0 +1,90 @@
+package testrunner_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/testrunner"
+	"github.com/henningstorck/monkey-interpreter/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func testResults() []*testrunner.Result {
+	failure := &object.Error{
+		Kind:     "AssertionError",
+		Message:  "assert_eq failed\n\texpected: 3\n\tactual:   2",
+		Position: token.Position{Line: 5, Column: 2},
+		Stack:    []object.StackFrame{{Function: "test_b"}},
+	}
+
+	return []*testrunner.Result{
+		{File: "a_test.monkey", Name: "test_a", Position: token.Position{Line: 1, Column: 4}, Duration: time.Millisecond},
+		{File: "a_test.monkey", Name: "test_b", Position: token.Position{Line: 4, Column: 4}, Duration: 2 * time.Millisecond, Error: failure},
+		{File: "b_test.monkey", Duration: 3 * time.Millisecond},
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var out strings.Builder
+	testrunner.WriteText(&out, testResults())
+
+	expected := `--- FAIL: test_b (a_test.monkey:4:4)
+    ERROR: assert_eq failed
+    	expected: 3
+    	actual:   2
+    	at test_b (5:2)
+FAIL a_test.monkey	0.003s
+ok   b_test.monkey	0.003s
+2 passed, 1 failed
+`
+
+	assert.Equal(t, expected, out.String())
+}
+
+func TestWriteTAP(t *testing.T) {
+	var out strings.Builder
+	testrunner.WriteTAP(&out, testResults())
+
+	expected := `TAP version 13
+1..3
+ok 1 - a_test.monkey: test_a
+not ok 2 - a_test.monkey: test_b
+  ---
+  at: a_test.monkey:4:4
+  message: |
+    ERROR: assert_eq failed
+    	expected: 3
+    	actual:   2
+    	at test_b (5:2)
+  ...
+ok 3 - b_test.monkey
+`
+
+	assert.Equal(t, expected, out.String())
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var out strings.Builder
+	assert.Nil(t, testrunner.WriteJUnit(&out, testResults()))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1" time="0.006">
+  <testsuite name="a_test.monkey" tests="2" failures="1" time="0.003">
+    <testcase name="test_a" classname="a_test.monkey" file="a_test.monkey" line="1" time="0.001"></testcase>
+    <testcase name="test_b" classname="a_test.monkey" file="a_test.monkey" line="4" time="0.002">
+      <failure message="assert_eq failed" type="AssertionError"><![CDATA[ERROR: assert_eq failed
+	expected: 3
+	actual:   2
+	at test_b (5:2)]]></failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="b_test.monkey" tests="1" failures="0" time="0.003">
+    <testcase name="b_test.monkey" classname="b_test.monkey" file="b_test.monkey" time="0.003"></testcase>
+  </testsuite>
+</testsuites>
+`
+
+	assert.Equal(t, expected, out.String())
+}
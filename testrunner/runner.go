@@ -0,0 +1,155 @@
+// Package testrunner finds and runs tests written in Monkey. A test file is a
+// script ending in _test.monkey, and each of its top-level functions named
+// test_* is a test.
+package testrunner
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/resolver"
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+const (
+	Suffix = "_test.monkey"
+	Prefix = "test_"
+)
+
+// Result is the outcome of a test. Files without test functions are run as a
+// single test without a name.
+type Result struct {
+	File     string
+	Name     string
+	Position token.Position
+	Duration time.Duration
+	Error    *object.Error
+}
+
+func (result *Result) Passed() bool {
+	return result.Error == nil
+}
+
+// Find returns the test files below the given directories, which default to
+// the working directory. Files are taken as they are.
+func Find(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	files := []string{}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() && strings.HasSuffix(file, Suffix) {
+				files = append(files, file)
+			}
+
+			return err
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// Run runs the tests of a file in source order. The program is evaluated in a
+// new environment for each test, so tests cannot see the bindings changed by
+// other tests. Imported modules are shared, as they are cached.
+func Run(file string, program *ast.Program) []*Result {
+	resolver.Resolve(program)
+	tests := findTests(program)
+
+	if len(tests) == 0 {
+		result := &Result{File: file}
+		start := time.Now()
+		_, result.Error = evaluate(file, program)
+		result.Duration = time.Since(start)
+		return []*Result{result}
+	}
+
+	results := make([]*Result, len(tests))
+
+	for i, test := range tests {
+		results[i] = &Result{File: file, Name: test.Value, Position: test.Pos()}
+		start := time.Now()
+		results[i].Error = runTest(file, program, test.Value)
+		results[i].Duration = time.Since(start)
+	}
+
+	return results
+}
+
+func runTest(file string, program *ast.Program, name string) *object.Error {
+	env, errObj := evaluate(file, program)
+
+	if errObj != nil {
+		return errObj
+	}
+
+	// A return before the binding skips it
+	fn, ok := env.Get(name)
+
+	if !ok {
+		return &object.Error{Kind: "RuntimeError", Message: "test function not bound: " + name}
+	}
+
+	errObj, _ = evaluator.Apply(name, fn).(*object.Error)
+	return errObj
+}
+
+func evaluate(file string, program *ast.Program) (*object.Environment, *object.Error) {
+	env := object.NewEnvironment()
+	evaluator.SetFile(env, file)
+	errObj, _ := evaluator.Eval(program, env).(*object.Error)
+	return env, errObj
+}
+
+// Test functions are bound by fn declarations or let statements, which may be
+// exported
+func findTests(program *ast.Program) []*ast.Identifier {
+	tests := []*ast.Identifier{}
+
+	for _, stmt := range program.Statements {
+		if exportStmt, ok := stmt.(*ast.ExportStatement); ok {
+			stmt = exportStmt.Statement
+		}
+
+		var name *ast.Identifier
+
+		switch stmt := stmt.(type) {
+		case *ast.FunctionDeclaration:
+			name = stmt.Function.Name
+		case *ast.LetStatement:
+			if _, ok := stmt.Value.(*ast.FunctionLiteral); ok {
+				name = stmt.Name
+			}
+		}
+
+		if name != nil && strings.HasPrefix(name.Value, Prefix) {
+			tests = append(tests, name)
+		}
+	}
+
+	return tests
+}
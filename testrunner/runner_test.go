@@ -0,0 +1,111 @@
+package testrunner_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/henningstorck/monkey-interpreter/testrunner"
+	"github.com/henningstorck/monkey-interpreter/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, file := range []string{"a_test.monkey", "a.monkey", "lib/b_test.monkey", "lib/b.monkey"} {
+		path := filepath.Join(dir, file)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.Nil(t, os.WriteFile(path, []byte(""), 0o644))
+	}
+
+	files, err := testrunner.Find([]string{dir})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a_test.monkey"), filepath.Join(dir, "lib/b_test.monkey")}, files)
+
+	files, err = testrunner.Find([]string{filepath.Join(dir, "a.monkey")})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a.monkey")}, files)
+
+	_, err = testrunner.Find([]string{filepath.Join(dir, "missing")})
+	assert.NotNil(t, err)
+}
+
+func TestRun(t *testing.T) {
+	input := `let total = 0;
+let add = fn(x) { x + 1 };
+
+fn test_add() {
+	assert_eq(add(1), 2);
+}
+
+let test_fails = fn() {
+	assert_eq(add(1), 3, "off by one");
+};
+
+export let test_isolated = fn() {
+	let total = total + 1;
+	assert_eq(total, 1);
+};
+
+let helper = fn() { assert(false) };`
+
+	results := testrunner.Run("add_test.monkey", parse(input))
+	assert.Len(t, results, 3)
+
+	tests := []struct {
+		name     string
+		position token.Position
+		message  string
+	}{
+		{"test_add", token.Position{Line: 4, Column: 4}, ""},
+		{"test_fails", token.Position{Line: 8, Column: 5}, "off by one: assert_eq failed\n\texpected: 3\n\tactual:   2\n\t          ^"},
+		{"test_isolated", token.Position{Line: 12, Column: 12}, ""},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, "add_test.monkey", results[i].File)
+		assert.Equal(t, test.name, results[i].Name)
+		assert.Equal(t, test.position, results[i].Position)
+		assert.Equal(t, test.message == "", results[i].Passed())
+
+		if test.message != "" {
+			assert.Equal(t, test.message, results[i].Error.Message)
+			assert.Equal(t, "test_fails", results[i].Error.Trace()[0].Function)
+		}
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	results := testrunner.Run("script_test.monkey", parse("assert_eq(1 + 1, 2)"))
+	assert.Len(t, results, 1)
+	assert.Equal(t, "", results[0].Name)
+	assert.True(t, results[0].Passed())
+
+	results = testrunner.Run("script_test.monkey", parse("let x = 1; assert(x > 1)"))
+	assert.Len(t, results, 1)
+	assert.False(t, results[0].Passed())
+}
+
+func TestRunSetupError(t *testing.T) {
+	results := testrunner.Run("broken_test.monkey", parse("fn test_a() { 1 }; 1 + true"))
+	assert.Len(t, results, 1)
+	assert.Equal(t, "type mismatch: INTEGER + BOOLEAN", results[0].Error.Message)
+}
+
+func TestRunUnboundTest(t *testing.T) {
+	results := testrunner.Run("early_test.monkey", parse("fn test_a() { 1 }; return 1; let test_b = fn() { 2 };"))
+	assert.Len(t, results, 2)
+	assert.True(t, results[0].Passed())
+	assert.False(t, results[1].Passed())
+	assert.Equal(t, "test function not bound: test_b", results[1].Error.Message)
+}
+
+func parse(input string) *ast.Program {
+	lex := lexer.NewLexer(input)
+	par := parser.NewParser(lex)
+	return par.ParseProgram()
+}
@@ -1,10 +1,22 @@
 package token
 
+import "fmt"
+
 type TokenType string
 
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (pos Position) String() string {
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+}
+
 type Token struct {
 	Type    TokenType
 	Literal string
+	Position
 }
 
 const (
@@ -16,6 +28,8 @@ const (
 	Int    = "INT"
 	String = "STRING"
 
+	Comment = "COMMENT"
+
 	// Operators
 	Assign   = "="
 	Bang     = "!"
@@ -29,10 +43,19 @@ const (
 
 	Eq    = "=="
 	NotEq = "!="
+	Arrow = "=>"
+	Pipe  = "|>"
+
+	Question      = "?"
+	NullCoalesce  = "??"
+	OptionalChain = "?."
 
 	// Delimeters
 	Comma     = ","
 	Semicolon = ";"
+	Dot       = "."
+	Colon     = ":"
+	Ellipsis  = "..."
 
 	LParen   = "("
 	RParen   = ")"
@@ -49,6 +72,16 @@ const (
 	If       = "IF"
 	Else     = "ELSE"
 	Return   = "RETURN"
+	Try      = "TRY"
+	Catch    = "CATCH"
+	Finally  = "FINALLY"
+	Throw    = "THROW"
+	Import   = "IMPORT"
+	Export   = "EXPORT"
+	Match    = "MATCH"
+	Switch   = "SWITCH"
+	Case     = "CASE"
+	Default  = "DEFAULT"
 )
 
 func NewToken(tokenType TokenType, char byte) Token {
@@ -56,13 +89,23 @@ func NewToken(tokenType TokenType, char byte) Token {
 }
 
 var keywords = map[string]TokenType{
-	"fn":     Function,
-	"let":    Let,
-	"true":   True,
-	"false":  False,
-	"if":     If,
-	"else":   Else,
-	"return": Return,
+	"fn":      Function,
+	"let":     Let,
+	"true":    True,
+	"false":   False,
+	"if":      If,
+	"else":    Else,
+	"return":  Return,
+	"try":     Try,
+	"catch":   Catch,
+	"finally": Finally,
+	"throw":   Throw,
+	"import":  Import,
+	"export":  Export,
+	"match":   Match,
+	"switch":  Switch,
+	"case":    Case,
+	"default": Default,
 }
 
 func LookupIdent(ident string) TokenType {
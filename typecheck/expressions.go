@@ -0,0 +1,448 @@
+package typecheck
+
+import (
+	"strings"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+func (chk *checker) checkExpression(exp ast.Expression) valueType {
+	switch exp := exp.(type) {
+	case nil:
+		return anyType
+	case *ast.IntegerLiteral:
+		return valueType{obj: object.IntegerObj}
+	case *ast.StringLiteral:
+		return valueType{obj: object.StringObj}
+	case *ast.BooleanLiteral:
+		return valueType{obj: object.BooleanObj}
+	case *ast.ArrayLiteral:
+		chk.checkExpressions(exp.Elements)
+		return valueType{obj: object.ArrayObj}
+	case *ast.HashLiteral:
+		for i, key := range exp.Keys {
+			chk.checkHashKey(key, chk.checkExpression(key))
+			chk.checkExpression(exp.Values[i])
+		}
+
+		return valueType{obj: object.HashObj}
+	case *ast.Identifier:
+		typ, ok := chk.lookup(exp.Value)
+
+		if !ok {
+			chk.errorf(exp, "identifier not found: %s", exp.Value)
+		}
+
+		return typ
+	case *ast.PrefixExpression:
+		return chk.checkPrefix(exp, chk.checkExpression(exp.Right))
+	case *ast.InfixExpression:
+		return chk.checkInfix(exp, chk.checkExpression(exp.Left), chk.checkExpression(exp.Right))
+	case *ast.ConditionalExpression:
+		chk.checkExpression(exp.Condition)
+		return join(chk.checkExpression(exp.Consequence), chk.checkExpression(exp.Alternative))
+	case *ast.IfExpression:
+		chk.checkExpression(exp.Condition)
+		consequence := chk.checkStatements(exp.Consequence.Statements)
+
+		if exp.Alternative == nil {
+			return anyType
+		}
+
+		return join(consequence, chk.checkStatements(exp.Alternative.Statements))
+	case *ast.FunctionLiteral:
+		return chk.checkFunction(exp)
+	case *ast.CallExpression:
+		return chk.checkCall(exp)
+	case *ast.SpreadExpression:
+		chk.checkExpression(exp.Value)
+		return anyType
+	case *ast.IndexExpression:
+		return chk.checkIndex(exp, chk.checkExpression(exp.Left), chk.checkExpression(exp.Index))
+	case *ast.SliceExpression:
+		left := chk.checkExpression(exp.Left)
+		chk.checkExpressions([]ast.Expression{exp.Start, exp.End, exp.Step})
+
+		if left.is(object.ArrayObj) || left.is(object.StringObj) {
+			return valueType{obj: left.obj}
+		}
+
+		return anyType
+	case *ast.MemberExpression:
+		left := chk.checkExpression(exp.Left)
+
+		if left.known() && !left.is(object.HashObj) && !left.is(object.ModuleObj) && !left.is(object.ExceptionObj) &&
+			!(exp.Optional && left.is(object.NullObj)) {
+			chk.errorf(exp, "member access is not supported: %s", left.obj)
+		}
+
+		return anyType
+	case *ast.ImportExpression:
+		chk.checkExpression(exp.Path)
+		return valueType{obj: object.ModuleObj}
+	case *ast.MatchExpression:
+		return chk.checkMatch(exp)
+	case *ast.SwitchExpression:
+		chk.checkExpression(exp.Subject)
+		types := []valueType{}
+
+		for _, switchCase := range exp.Cases {
+			chk.checkExpressions(switchCase.Values)
+			types = append(types, chk.checkStatements(switchCase.Body.Statements))
+		}
+
+		if exp.Default == nil {
+			return anyType
+		}
+
+		return join(append(types, chk.checkStatements(exp.Default.Statements))...)
+	case *ast.TryExpression:
+		chk.checkTry(exp)
+		return anyType
+	default:
+		return anyType
+	}
+}
+
+func (chk *checker) checkExpressions(exps []ast.Expression) {
+	for _, exp := range exps {
+		chk.checkExpression(exp)
+	}
+}
+
+func (chk *checker) checkHashKey(node ast.Node, typ valueType) {
+	if typ.known() && !typ.is(object.IntegerObj) && !typ.is(object.StringObj) && !typ.is(object.BooleanObj) {
+		chk.errorf(node, "unusable as hash key: %s", typ.obj)
+	}
+}
+
+func (chk *checker) checkPrefix(exp *ast.PrefixExpression, right valueType) valueType {
+	switch exp.Operator {
+	case "!":
+		return valueType{obj: object.BooleanObj}
+	case "-":
+		if right.known() && !right.is(object.IntegerObj) {
+			chk.errorf(exp, "unknown operator: -%s", right.obj)
+		}
+
+		return valueType{obj: object.IntegerObj}
+	default:
+		return anyType
+	}
+}
+
+// Follows the rules of the evaluator. If only one side is known, the result
+// is the only type the operator could produce without an error.
+func (chk *checker) checkInfix(exp *ast.InfixExpression, left, right valueType) valueType {
+	if exp.Operator == "??" {
+		if left.is(object.NullObj) {
+			return right
+		}
+
+		if left.known() {
+			return left
+		}
+
+		return join(left, right)
+	}
+
+	if left.known() && right.known() {
+		result, ok := infixType(exp.Operator, left, right)
+
+		if !ok && left.obj != right.obj {
+			chk.errorf(exp, "type mismatch: %s %s %s", left.obj, exp.Operator, right.obj)
+		} else if !ok {
+			chk.errorf(exp, "unknown operator: %s %s %s", left.obj, exp.Operator, right.obj)
+		}
+
+		return result
+	}
+
+	switch exp.Operator {
+	case "<", ">", "==", "!=":
+		return valueType{obj: object.BooleanObj}
+	case "-", "*", "/":
+		return valueType{obj: object.IntegerObj}
+	case "+":
+		return join(left, right)
+	default:
+		return anyType
+	}
+}
+
+func infixType(operator string, left, right valueType) (valueType, bool) {
+	switch {
+	case left.is(object.IntegerObj) && right.is(object.IntegerObj):
+		switch operator {
+		case "+", "-", "*", "/":
+			return valueType{obj: object.IntegerObj}, true
+		case "<", ">", "==", "!=":
+			return valueType{obj: object.BooleanObj}, true
+		}
+	case left.is(object.StringObj) && right.is(object.StringObj):
+		if operator == "+" {
+			return valueType{obj: object.StringObj}, true
+		}
+	case operator == "==" || operator == "!=":
+		return valueType{obj: object.BooleanObj}, true
+	}
+
+	return anyType, false
+}
+
+func (chk *checker) checkIndex(exp *ast.IndexExpression, left, index valueType) valueType {
+	if !left.known() {
+		return anyType
+	}
+
+	switch {
+	case left.is(object.HashObj):
+		chk.checkHashKey(exp.Index, index)
+		return anyType
+	case !index.known():
+		if !left.is(object.ArrayObj) && !left.is(object.StringObj) && !left.is(object.ExceptionObj) && !left.is(object.ModuleObj) {
+			chk.errorf(exp, "index operator is not supported: %s", left.obj)
+		}
+	case left.is(object.ArrayObj) && index.is(object.IntegerObj):
+	case left.is(object.StringObj) && index.is(object.IntegerObj):
+	case (left.is(object.ExceptionObj) || left.is(object.ModuleObj)) && index.is(object.StringObj):
+	default:
+		chk.errorf(exp, "index operator is not supported: %s", left.obj)
+	}
+
+	return anyType
+}
+
+// Parameters have the type of their annotation. Functions without a return
+// annotation return the type all their results agree on.
+func (chk *checker) checkFunction(fnLiteral *ast.FunctionLiteral) valueType {
+	sig := newSignature(fnLiteral)
+	chk.push(true)
+
+	for i, param := range fnLiteral.Parameters {
+		chk.current().bindings[param.Value] = &binding{typ: anyType}
+
+		if i < len(fnLiteral.Defaults) && fnLiteral.Defaults[i] != nil {
+			if typ := chk.checkExpression(fnLiteral.Defaults[i]); !typ.fits(sig.types[i].obj) {
+				chk.errorf(fnLiteral.Defaults[i], "%s: expected %s, got %s", param.Value, sig.types[i].annotation(), typ.annotation())
+			}
+		}
+
+		chk.bind(param.Value, sig.types[i])
+	}
+
+	if fnLiteral.Rest != nil {
+		chk.bind(fnLiteral.Rest.Value, valueType{obj: object.ArrayObj})
+	}
+
+	declare(chk.current(), fnLiteral.Body)
+	outer := chk.function
+	chk.function = &function{sig: sig}
+	result := chk.checkStatements(fnLiteral.Body.Statements)
+	returns := chk.function.returns
+	stmts := fnLiteral.Body.Statements
+
+	// A return as the last statement has been checked already
+	if len(stmts) > 0 {
+		if _, ok := stmts[len(stmts)-1].(*ast.ReturnStatement); !ok {
+			if _, ok := stmts[len(stmts)-1].(*ast.ExpressionStatement); ok {
+				chk.checkReturn(stmts[len(stmts)-1], result)
+			} else {
+				result = anyType
+			}
+
+			returns = append(returns, result)
+		}
+	}
+
+	if !sig.declared {
+		sig.returns = join(returns...)
+	}
+
+	chk.function = outer
+	chk.pop()
+	return valueType{obj: object.FunctionObj, fn: sig}
+}
+
+func (chk *checker) checkMatch(matchExp *ast.MatchExpression) valueType {
+	chk.checkExpression(matchExp.Subject)
+	types := []valueType{}
+
+	for _, arm := range matchExp.Arms {
+		chk.push(false)
+
+		for _, name := range ast.PatternNames(arm.Pattern) {
+			chk.current().bindings[name] = &binding{typ: anyType}
+		}
+
+		if arm.Guard != nil {
+			declare(chk.current(), arm.Guard)
+		}
+
+		declare(chk.current(), arm.Body)
+		chk.bindPattern(arm.Pattern, anyType)
+		chk.checkExpression(arm.Guard)
+		types = append(types, chk.checkExpression(arm.Body))
+		chk.pop()
+	}
+
+	return join(types...)
+}
+
+func (chk *checker) checkTry(tryExp *ast.TryExpression) {
+	chk.checkStatements(tryExp.Block.Statements)
+
+	if tryExp.Catch != nil {
+		chk.push(false)
+		declare(chk.current(), tryExp.Catch)
+		chk.bind(tryExp.Parameter.Value, valueType{obj: object.ExceptionObj})
+		chk.checkStatements(tryExp.Catch.Statements)
+		chk.pop()
+	}
+
+	if tryExp.Finally != nil {
+		chk.checkStatements(tryExp.Finally.Statements)
+	}
+}
+
+// Arguments are only checked if the function being called is known and no
+// arrays are spread into the call
+func (chk *checker) checkCall(call *ast.CallExpression) valueType {
+	fn := chk.checkExpression(call.Function)
+	args := make([]valueType, len(call.Arguments))
+	spread := false
+
+	for i, arg := range call.Arguments {
+		args[i] = chk.checkExpression(arg)
+
+		if _, ok := arg.(*ast.SpreadExpression); ok {
+			spread = true
+		}
+	}
+
+	keywords := make([]valueType, len(call.Keywords))
+
+	for i, keyword := range call.Keywords {
+		keywords[i] = chk.checkExpression(keyword.Value)
+	}
+
+	if fn.known() && !fn.callable() {
+		chk.errorf(call, "not a function: %s", fn.obj)
+		return anyType
+	}
+
+	if fn.fn == nil || spread {
+		return anyType
+	}
+
+	if fn.fn.builtin != nil {
+		chk.checkBuiltinCall(call, fn.fn.builtin, args)
+	} else {
+		chk.checkFunctionCall(call, fn.fn, args, keywords)
+	}
+
+	return fn.fn.returns
+}
+
+func (chk *checker) checkBuiltinCall(call *ast.CallExpression, sig *object.Signature, args []valueType) {
+	if len(call.Keywords) > 0 {
+		chk.errorf(call, "keyword arguments are not supported by %s", object.BuiltinObj)
+		return
+	}
+
+	min, max := sig.Arity()
+
+	if msg := object.ArityError(len(args), min, max); msg != "" {
+		chk.errorf(call, "%s", msg)
+		return
+	}
+
+	for i, arg := range args {
+		param, _ := sig.Parameter(i)
+
+		if !arg.known() || param.Accepts(arg.obj) || (arg.is(object.FunctionObj) && param.Accepts(object.BuiltinObj)) {
+			continue
+		}
+
+		types := make([]string, len(param.Types))
+
+		for j, paramType := range param.Types {
+			types[j] = string(paramType)
+		}
+
+		chk.errorf(call.Arguments[i], "invalid argument. got %s, but expected %s", arg.obj, strings.Join(types, " or "))
+	}
+}
+
+// Keyword arguments are put at the position of their parameter, like the
+// evaluator does
+func (chk *checker) checkFunctionCall(call *ast.CallExpression, sig *signature, args, keywords []valueType) {
+	if len(call.Keywords) > 0 && len(args) > len(sig.params) {
+		chk.errorf(call, "wrong number of arguments. got %d, but expected at most %d before keyword arguments", len(args), len(sig.params))
+		return
+	}
+
+	nodes := append([]ast.Node{}, nodesOf(call.Arguments)...)
+	given := append([]valueType{}, args...)
+	passed := make([]bool, len(args))
+
+	for i := range passed {
+		passed[i] = true
+	}
+
+	for i, keyword := range call.Keywords {
+		index := -1
+
+		for j, param := range sig.params {
+			if param.Value == keyword.Name.Value {
+				index = j
+			}
+		}
+
+		if index < 0 {
+			chk.errorf(keyword.Name, "unexpected keyword argument: %s", keyword.Name.Value)
+			return
+		}
+
+		for len(given) <= index {
+			given = append(given, anyType)
+			nodes = append(nodes, nil)
+			passed = append(passed, false)
+		}
+
+		if passed[index] {
+			chk.errorf(keyword.Name, "got multiple values for argument: %s", keyword.Name.Value)
+			return
+		}
+
+		given[index], nodes[index], passed[index] = keywords[i], keyword.Value, true
+	}
+
+	min, max := ast.ParametersArity(sig.params, sig.defaults, sig.rest)
+
+	if msg := object.ArityError(len(given), min, max); msg != "" {
+		chk.errorf(call, "%s", msg)
+		return
+	}
+
+	for i, param := range sig.params {
+		if i < len(passed) && passed[i] {
+			if !given[i].fits(sig.types[i].obj) {
+				chk.errorf(nodes[i], "argument %s of %s: expected %s, got %s", param.Value, functionName(sig), sig.types[i].annotation(), given[i].annotation())
+			}
+		} else if i >= len(sig.defaults) || sig.defaults[i] == nil {
+			chk.errorf(call, "missing argument: %s", param.Value)
+		}
+	}
+}
+
+func nodesOf(exps []ast.Expression) []ast.Node {
+	nodes := make([]ast.Node, len(exps))
+
+	for i, exp := range exps {
+		nodes[i] = exp
+	}
+
+	return nodes
+}
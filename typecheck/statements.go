@@ -0,0 +1,119 @@
+package typecheck
+
+import (
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/object"
+)
+
+// Returns the type of the value of the last statement. Function declarations
+// are bound before the statements are checked, like the evaluator does.
+func (chk *checker) checkStatements(stmts []ast.Statement) valueType {
+	for _, stmt := range stmts {
+		if fnDecl, ok := stmt.(*ast.FunctionDeclaration); ok {
+			chk.bind(fnDecl.Function.Name.Value, valueType{obj: object.FunctionObj, fn: newSignature(fnDecl.Function)})
+		}
+	}
+
+	result := anyType
+
+	for _, stmt := range stmts {
+		result = chk.checkStatement(stmt)
+	}
+
+	return result
+}
+
+func (chk *checker) checkStatement(stmt ast.Statement) valueType {
+	switch stmt := stmt.(type) {
+	case *ast.ExpressionStatement:
+		return chk.checkExpression(stmt.Expression)
+	case *ast.LetStatement:
+		chk.checkLet(stmt)
+	case *ast.ExportStatement:
+		chk.checkLet(stmt.Statement)
+	case *ast.FunctionDeclaration:
+		typ := chk.checkFunction(stmt.Function)
+		chk.bind(stmt.Function.Name.Value, typ)
+	case *ast.ReturnStatement:
+		typ := chk.checkExpression(stmt.ReturnValue)
+
+		if chk.function != nil {
+			chk.checkReturn(stmt, typ)
+			chk.function.returns = append(chk.function.returns, typ)
+		}
+	case *ast.ThrowStatement:
+		chk.checkExpression(stmt.Value)
+	case *ast.BlockStatement:
+		return chk.checkStatements(stmt.Statements)
+	}
+
+	return anyType
+}
+
+// An annotated name keeps the type of its annotation, which the value has to
+// fit
+func (chk *checker) checkLet(letStmt *ast.LetStatement) {
+	typ := chk.checkExpression(letStmt.Value)
+
+	// Functions bound by let are named after their binding in errors
+	if _, ok := letStmt.Value.(*ast.FunctionLiteral); ok && typ.fn != nil && typ.fn.name == "" && letStmt.Name != nil {
+		typ.fn.name = letStmt.Name.Value
+	}
+
+	if letStmt.Pattern != nil {
+		chk.bindPattern(letStmt.Pattern, typ)
+		return
+	}
+
+	if letStmt.Type != "" {
+		declared := annotated(letStmt.Type)
+
+		if !typ.fits(declared.obj) {
+			chk.errorf(letStmt.Value, "%s: expected %s, got %s", letStmt.Name.Value, letStmt.Type, typ.annotation())
+		}
+
+		if typ.fn == nil || !typ.fits(declared.obj) {
+			typ = declared
+		}
+	}
+
+	chk.bind(letStmt.Name.Value, typ)
+}
+
+// Names bound by patterns have an unknown type unless a type pattern tells
+// them apart
+func (chk *checker) bindPattern(pattern ast.Pattern, typ valueType) {
+	ast.Walk(pattern, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.TypePattern:
+			chk.bind(node.Target.Value, annotated(node.Type))
+			return false
+		case *ast.DefaultPattern:
+			chk.checkExpression(node.Default)
+			chk.bindPattern(node.Target, anyType)
+			return false
+		case *ast.Identifier:
+			if !ast.IsWildcard(node) {
+				chk.bind(node.Value, anyType)
+			}
+		}
+
+		return true
+	})
+}
+
+func (chk *checker) checkReturn(node ast.Node, typ valueType) {
+	sig := chk.function.sig
+
+	if sig.declared && !typ.fits(sig.returns.obj) {
+		chk.errorf(node, "%s: expected to return %s, got %s", functionName(sig), sig.returns.annotation(), typ.annotation())
+	}
+}
+
+func functionName(sig *signature) string {
+	if sig.name == "" {
+		return "fn"
+	}
+
+	return sig.name
+}
@@ -0,0 +1,262 @@
+// Package typecheck finds type errors in a program before it runs. The types
+// of expressions are inferred locally from literals, operators, the
+// signatures of builtins and the optional annotations of let statements and
+// functions, like let x: int = 5 or fn(a: int, b: string): bool { ... }.
+//
+// Whatever cannot be inferred has the type ANY, which is compatible with
+// everything, so code without annotations is accepted unless it is certain to
+// fail. The errors use the same messages as the evaluator.
+package typecheck
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/evaluator"
+	"github.com/henningstorck/monkey-interpreter/object"
+	"github.com/henningstorck/monkey-interpreter/token"
+)
+
+// Error is a problem found in the program.
+type Error struct {
+	Position token.Position
+	Message  string
+}
+
+func (err Error) String() string {
+	return err.Position.String() + ": " + err.Message
+}
+
+// The object types of the names used in annotations and type patterns
+var annotationTypes = map[string]object.ObjectType{
+	"int":       object.IntegerObj,
+	"string":    object.StringObj,
+	"bool":      object.BooleanObj,
+	"array":     object.ArrayObj,
+	"hash":      object.HashObj,
+	"fn":        object.FunctionObj,
+	"null":      object.NullObj,
+	"module":    object.ModuleObj,
+	"exception": object.ExceptionObj,
+}
+
+// valueType is the type of an expression. Functions carry their signature if
+// it is known.
+type valueType struct {
+	obj object.ObjectType
+	fn  *signature
+}
+
+var anyType = valueType{obj: object.AnyObj}
+
+func (typ valueType) known() bool {
+	return typ.obj != object.AnyObj
+}
+
+func (typ valueType) is(obj object.ObjectType) bool {
+	return typ.obj == obj
+}
+
+func (typ valueType) callable() bool {
+	return typ.obj == object.FunctionObj || typ.obj == object.BuiltinObj
+}
+
+// Reports whether a value of the type may be bound to an annotation of the
+// other one. Builtins are functions as well.
+func (typ valueType) fits(annotation object.ObjectType) bool {
+	return !typ.known() || annotation == object.AnyObj || typ.obj == annotation ||
+		(annotation == object.FunctionObj && typ.obj == object.BuiltinObj)
+}
+
+// The name used in annotations, so errors about annotations read like them
+func (typ valueType) annotation() string {
+	for name, obj := range annotationTypes {
+		if obj == typ.obj {
+			return name
+		}
+	}
+
+	if typ.obj == object.BuiltinObj {
+		return "fn"
+	}
+
+	return "any"
+}
+
+// The type of values of a type named by an annotation, which is ANY if it is
+// left out
+func annotated(name string) valueType {
+	if obj, ok := annotationTypes[name]; ok {
+		return valueType{obj: obj}
+	}
+
+	return anyType
+}
+
+// Both branches of a conditional need to agree on a type
+func join(types ...valueType) valueType {
+	if len(types) == 0 {
+		return anyType
+	}
+
+	for _, typ := range types[1:] {
+		if typ.obj != types[0].obj {
+			return anyType
+		}
+	}
+
+	return valueType{obj: types[0].obj}
+}
+
+// signature describes a function defined in the program or a builtin.
+type signature struct {
+	name     string
+	params   []*ast.Identifier
+	types    []valueType
+	defaults []ast.Expression
+	rest     *ast.Identifier
+	returns  valueType
+	declared bool
+	builtin  *object.Signature
+}
+
+func newSignature(fnLiteral *ast.FunctionLiteral) *signature {
+	sig := &signature{
+		params:   fnLiteral.Parameters,
+		defaults: fnLiteral.Defaults,
+		rest:     fnLiteral.Rest,
+		returns:  annotated(fnLiteral.ReturnType),
+		declared: fnLiteral.ReturnType != "",
+	}
+
+	if fnLiteral.Name != nil {
+		sig.name = fnLiteral.Name.Value
+	}
+
+	for i := range fnLiteral.Parameters {
+		if i < len(fnLiteral.Types) {
+			sig.types = append(sig.types, annotated(fnLiteral.Types[i]))
+		} else {
+			sig.types = append(sig.types, anyType)
+		}
+	}
+
+	return sig
+}
+
+// A binding is declared once the statement binding it has been checked.
+// Closures may refer to it before.
+type binding struct {
+	typ      valueType
+	declared bool
+}
+
+// Scopes are the same as the environments of the evaluator: the program,
+// function bodies, match arms and catch blocks
+type scope struct {
+	bindings map[string]*binding
+	function bool
+}
+
+// function collects the types returned by the function being checked
+type function struct {
+	sig     *signature
+	returns []valueType
+}
+
+type checker struct {
+	errors   []Error
+	scopes   []*scope
+	function *function
+	builtins map[string]*signature
+}
+
+// Check returns the errors found in the program in source order.
+func Check(program *ast.Program) []Error {
+	chk := &checker{builtins: make(map[string]*signature)}
+
+	for _, builtin := range evaluator.Builtins() {
+		builtinSig := builtin.Signature
+		chk.builtins[builtinSig.Name] = &signature{name: builtinSig.Name, builtin: &builtinSig, returns: valueType{obj: builtinSig.Returns}}
+
+		if builtinSig.Returns == "" {
+			chk.builtins[builtinSig.Name].returns = anyType
+		}
+	}
+
+	chk.push(false)
+	declare(chk.current(), program)
+	chk.checkStatements(program.Statements)
+	chk.pop()
+
+	sort.SliceStable(chk.errors, func(i, j int) bool {
+		left, right := chk.errors[i].Position, chk.errors[j].Position
+		return left.Line < right.Line || (left.Line == right.Line && left.Column < right.Column)
+	})
+
+	return chk.errors
+}
+
+func (chk *checker) errorf(node ast.Node, format string, args ...any) {
+	chk.errors = append(chk.errors, Error{Position: node.Pos(), Message: fmt.Sprintf(format, args...)})
+}
+
+func (chk *checker) push(function bool) *scope {
+	sc := &scope{bindings: make(map[string]*binding), function: function}
+	chk.scopes = append(chk.scopes, sc)
+	return sc
+}
+
+func (chk *checker) pop() {
+	chk.scopes = chk.scopes[:len(chk.scopes)-1]
+}
+
+func (chk *checker) current() *scope {
+	return chk.scopes[len(chk.scopes)-1]
+}
+
+// Binds a name in the innermost scope, where it has been declared
+func (chk *checker) bind(name string, typ valueType) {
+	chk.current().bindings[name] = &binding{typ: typ, declared: true}
+}
+
+// Looks a name up like the evaluator does. A name which has not been bound in
+// the current function yet falls back to an outer binding. Closures may refer
+// to bindings of enclosing functions which are bound later, but their type is
+// not known yet.
+func (chk *checker) lookup(name string) (valueType, bool) {
+	crossed := false
+
+	for i := len(chk.scopes) - 1; i >= 0; i-- {
+		if b, ok := chk.scopes[i].bindings[name]; ok {
+			if b.declared {
+				return b.typ, true
+			}
+
+			if crossed {
+				return anyType, true
+			}
+		}
+
+		if chk.scopes[i].function {
+			crossed = true
+		}
+	}
+
+	if sig, ok := chk.builtins[name]; ok {
+		return valueType{obj: object.BuiltinObj, fn: sig}, true
+	}
+
+	return anyType, false
+}
+
+// Declares the names bound by the node in the scope, without descending into
+// nested scopes
+func declare(sc *scope, node ast.Node) {
+	ast.WalkDeclarations(node, func(ident *ast.Identifier, _ ast.Statement) {
+		if sc.bindings[ident.Value] == nil {
+			sc.bindings[ident.Value] = &binding{typ: anyType}
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package typecheck_test
+
+import (
+	"testing"
+
+	"github.com/henningstorck/monkey-interpreter/ast"
+	"github.com/henningstorck/monkey-interpreter/lexer"
+	"github.com/henningstorck/monkey-interpreter/parser"
+	"github.com/henningstorck/monkey-interpreter/typecheck"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"1 + \"a\"", []string{"1:3: type mismatch: INTEGER + STRING"}},
+		{"\"a\" - \"b\"", []string{"1:5: unknown operator: STRING - STRING"}},
+		{"true + false", []string{"1:6: unknown operator: BOOLEAN + BOOLEAN"}},
+		{"-\"a\"", []string{"1:1: unknown operator: -STRING"}},
+		{"let x = 1; let y = x * 2; y + \"a\"", []string{"1:29: type mismatch: INTEGER + STRING"}},
+		{"let x: int = \"a\"", []string{"1:14: x: expected int, got string"}},
+		{"let x: string = 1; x - 1", []string{"1:17: x: expected string, got int", "1:22: type mismatch: STRING - INTEGER"}},
+		{"fn f(a: int): string { a } f(\"x\")", []string{"1:24: f: expected to return string, got int", "1:30: argument a of f: expected int, got string"}},
+		{"let f = fn(a: int, b = 1) { a }; f(); f(1, 2, 3)", []string{"1:35: wrong number of arguments. got 0, but expected 1 to 2", "1:40: wrong number of arguments. got 3, but expected 1 to 2"}},
+		{"let f = fn(a, ...b) { a }; f()", []string{"1:29: wrong number of arguments. got 0, but expected at least 1"}},
+		{"let f = fn(a, b: bool) { a }; f(b: 1, a: 2); f(1, c: 2); f(1, a: 2)", []string{"1:36: argument b of f: expected bool, got int", "1:51: unexpected keyword argument: c", "1:63: got multiple values for argument: a"}},
+		{"len(1, 2); len(1); len([1], foo: 1)", []string{"1:4: wrong number of arguments. got 2, but expected 1", "1:16: invalid argument. got INTEGER, but expected STRING or ARRAY", "1:23: keyword arguments are not supported by BUILTIN"}},
+		{"x; fn() { y }", []string{"1:1: identifier not found: x", "1:11: identifier not found: y"}},
+		{"1(); let a = [1]; a[\"x\"]; a.b", []string{"1:2: not a function: INTEGER", "1:20: index operator is not supported: ARRAY", "1:28: member access is not supported: ARRAY"}},
+		{"{[1]: 2}", []string{"1:2: unusable as hash key: ARRAY"}},
+		{"fn(): int { if (true) { return \"a\" } 1 }", []string{"1:25: fn: expected to return int, got string"}},
+		{"let f = fn() { 1 }; f() + \"a\"", []string{"1:25: type mismatch: INTEGER + STRING"}},
+		{"let f = fn(x) { if (x) { 1 } else { \"a\" } }; f(true) + \"a\"", nil},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, check(t, test.input), test.input)
+	}
+}
+
+func TestCheckAcceptsUnannotatedCode(t *testing.T) {
+	tests := []string{
+		"let add = fn(a, b) { a + b }; add(1, 2); add(\"a\", \"b\")",
+		"let f = fn() { g() }; let g = fn() { 1 }; f()",
+		"fn even(n) { if (n == 0) { true } else { odd(n - 1) } } fn odd(n) { if (n == 0) { false } else { even(n - 1) } } even(4)",
+		"let x = first([]) ?? 1; x + 1",
+		"let r: array = rest([1]); let s: null = rest([])",
+		"let h = {\"a\": 1}; h.a + h[\"a\"]",
+		"let xs = [1, 2]; let f = fn(a, b) { a }; f(...xs)",
+		"match ([1, 2]) { [a, b] => a + b, x: string => x + \"!\", _ => 0 }",
+		"try { throw \"x\" } catch (e) { e.message + \"!\" }",
+		"let [a, b = 2] = [1]; a + b",
+		"let m = import \"lib\"; m.f(1)",
+		"let f: fn = len; f(\"a\"); let g: fn = fn(x: int) { x }; g(1)",
+		"1 == \"a\"; [1] != [1]; push([], \"a\")",
+	}
+
+	for _, input := range tests {
+		assert.Empty(t, check(t, input), input)
+	}
+}
+
+func check(t *testing.T, input string) []string {
+	program := testParse(t, input)
+	var messages []string
+
+	for _, err := range typecheck.Check(program) {
+		messages = append(messages, err.String())
+	}
+
+	return messages
+}
+
+func testParse(t *testing.T, input string) *ast.Program {
+	lex := lexer.NewLexer(input)
+	par := parser.NewParser(lex)
+	program := par.ParseProgram()
+	assert.Empty(t, par.Errors(), input)
+	return program
+}